@@ -0,0 +1,264 @@
+package verbose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AdminHandler is an http.Handler to view and change the levels of
+// registered loggers at runtime. It should be mounted behind authentication.
+//
+// GET lists all loggers with their handlers and levels. The logger query
+// parameter limits the list to a single logger.
+//
+// PUT or POST with the logger query parameter changes the levels of all the
+// logger's handlers, or a single handler if the handler query parameter is
+// given. Only handlers implementing LevelReporter and LevelRangeSetter can be
+// changed, others are skipped. The body is a JSON object with either "level" or "min_level" and/or
+// "max_level". An optional "duration", such as "10m", reverts the change
+// once it's passed.
+//
+//	curl -X PUT 'localhost:8080/logging?logger=app' -d '{"level": "debug", "duration": "10m"}'
+type AdminHandler struct {
+	reverts map[string]*adminRevert
+	m       sync.Mutex
+}
+
+// adminRevert holds the levels a handler had before a temporary change.
+type adminRevert struct {
+	timer *time.Timer
+	min   LogLevel
+	max   LogLevel
+}
+
+// AdminLogger describes a logger in AdminHandler responses.
+type AdminLogger struct {
+	Name     string             `json:"name"`
+	Handlers []AdminHandlerInfo `json:"handlers"`
+}
+
+// AdminHandlerInfo describes a handler in AdminHandler responses. The levels
+// are empty if the handler doesn't implement LevelReporter.
+type AdminHandlerInfo struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	MinLevel string `json:"min_level,omitempty"`
+	MaxLevel string `json:"max_level,omitempty"`
+}
+
+// AdminLevelRequest is the body of a PUT or POST request to AdminHandler.
+type AdminLevelRequest struct {
+	Level    string `json:"level,omitempty"`
+	MinLevel string `json:"min_level,omitempty"`
+	MaxLevel string `json:"max_level,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// NewAdminHandler creates an AdminHandler.
+func NewAdminHandler() *AdminHandler {
+	return &AdminHandler{
+		reverts: make(map[string]*adminRevert),
+	}
+}
+
+func (a *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("logger")
+
+	switch r.Method {
+	case http.MethodGet:
+		if name == "" {
+			adminJSON(w, http.StatusOK, describeLoggers())
+			return
+		}
+	case http.MethodPut, http.MethodPost:
+		if name == "" {
+			adminError(w, http.StatusBadRequest, errors.New("logger parameter is required"))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		adminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	l := getLogger(name)
+	if l == nil {
+		adminError(w, http.StatusNotFound, fmt.Errorf("logger %q not found", name))
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		var req AdminLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			adminError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %v", err))
+			return
+		}
+		if status, err := a.setLevels(l, r.URL.Query().Get("handler"), req); err != nil {
+			adminError(w, status, err)
+			return
+		}
+	}
+	adminJSON(w, http.StatusOK, describeLogger(l))
+}
+
+func (a *AdminHandler) setLevels(l *Logger, handler string, req AdminLevelRequest) (int, error) {
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			return http.StatusBadRequest, fmt.Errorf("invalid duration %q", req.Duration)
+		}
+		duration = d
+	}
+
+	cfg := HandlerConfig{Level: req.Level, MinLevel: req.MinLevel, MaxLevel: req.MaxLevel}
+	if cfg.Level == "" && cfg.MinLevel == "" && cfg.MaxLevel == "" {
+		return http.StatusBadRequest, errors.New("level, min_level or max_level is required")
+	}
+	if _, _, err := configLevels(cfg); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	l.m.RLock()
+	defer l.m.RUnlock()
+
+	handlers := make(map[string]adminLevelHandler, len(l.handlers))
+	if handler != "" {
+		h, ok := l.handlers[handler]
+		if !ok {
+			return http.StatusNotFound, fmt.Errorf("handler %q not found", handler)
+		}
+		lh, ok := h.(adminLevelHandler)
+		if !ok {
+			return http.StatusBadRequest, fmt.Errorf("handler %q can't change levels at runtime", handler)
+		}
+		handlers[handler] = lh
+	} else {
+		for hn, h := range l.handlers {
+			if lh, ok := h.(adminLevelHandler); ok {
+				handlers[hn] = lh
+			}
+		}
+	}
+	if len(handlers) == 0 {
+		return http.StatusBadRequest, fmt.Errorf("logger %q has no handlers that can change levels at runtime", l.Name())
+	}
+
+	type change struct {
+		h                        adminLevelHandler
+		min, max, newMin, newMax LogLevel
+	}
+	changes := make(map[string]change, len(handlers))
+	for hn, h := range handlers {
+		c := change{h: h}
+		c.min, c.max = h.MinLevel(), h.MaxLevel()
+		c.newMin, c.newMax = c.min, c.max
+		if cfg.Level != "" {
+			c.newMin, _ = ParseLevel(cfg.Level)
+			c.newMax = c.newMin
+		}
+		if cfg.MinLevel != "" {
+			c.newMin, _ = ParseLevel(cfg.MinLevel)
+		}
+		if cfg.MaxLevel != "" {
+			c.newMax, _ = ParseLevel(cfg.MaxLevel)
+		}
+		if c.newMin > c.newMax {
+			return http.StatusBadRequest, fmt.Errorf("handler %q: min level %s is above max level %s", hn, c.newMin, c.newMax)
+		}
+		changes[hn] = c
+	}
+
+	a.m.Lock()
+	defer a.m.Unlock()
+	for hn, c := range changes {
+		a.scheduleRevert(l.Name()+"\x00"+hn, c.h, c.min, c.max, duration)
+		c.h.SetLevelRange(c.newMin, c.newMax)
+	}
+	return http.StatusOK, nil
+}
+
+// scheduleRevert sets up reverting h to min and max after d. If a revert is
+// already pending, the original levels are kept. If d is 0, any pending
+// revert is cancelled and the change is permanent. The admin lock must be held.
+func (a *AdminHandler) scheduleRevert(key string, h adminLevelHandler, min, max LogLevel, d time.Duration) {
+	if old, pending := a.reverts[key]; pending {
+		old.timer.Stop()
+		delete(a.reverts, key)
+		min, max = old.min, old.max
+	}
+	if d == 0 {
+		return
+	}
+
+	rev := &adminRevert{min: min, max: max}
+
+	rev.timer = time.AfterFunc(d, func() {
+		a.m.Lock()
+		defer a.m.Unlock()
+		if a.reverts[key] != rev {
+			return
+		}
+		delete(a.reverts, key)
+		h.SetLevelRange(rev.min, rev.max)
+	})
+	a.reverts[key] = rev
+}
+
+// adminLevelHandler is a handler whose levels can be changed by AdminHandler.
+// Its current levels must be known to revert a change.
+type adminLevelHandler interface {
+	LevelReporter
+	LevelRangeSetter
+}
+
+func describeLoggers() []AdminLogger {
+	loggersMutex.RLock()
+	ls := make([]*Logger, 0, len(loggers))
+	for _, l := range loggers {
+		ls = append(ls, l)
+	}
+	loggersMutex.RUnlock()
+
+	sort.Slice(ls, func(i, j int) bool { return ls[i].Name() < ls[j].Name() })
+	out := make([]AdminLogger, len(ls))
+	for i, l := range ls {
+		out[i] = describeLogger(l)
+	}
+	return out
+}
+
+func describeLogger(l *Logger) AdminLogger {
+	l.m.RLock()
+	defer l.m.RUnlock()
+
+	out := AdminLogger{
+		Name:     l.Name(),
+		Handlers: make([]AdminHandlerInfo, 0, len(l.handlers)),
+	}
+	for hn, h := range l.handlers {
+		info := AdminHandlerInfo{Name: hn, Type: fmt.Sprintf("%T", h)}
+		if lr, ok := h.(LevelReporter); ok {
+			info.MinLevel = lr.MinLevel().String()
+			info.MaxLevel = lr.MaxLevel().String()
+		}
+		out.Handlers = append(out.Handlers, info)
+	}
+	sort.Slice(out.Handlers, func(i, j int) bool { return out.Handlers[i].Name < out.Handlers[j].Name })
+	return out
+}
+
+func adminJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func adminError(w http.ResponseWriter, status int, err error) {
+	adminJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,135 @@
+package verbose
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func adminRequest(t *testing.T, a *AdminHandler, method, url, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminHandlerList(t *testing.T) {
+	clearLoggers()
+	New("app").AddHandler("stdout", NewStdoutHandler(false))
+	New("db").AddHandler("test", &testHandler{})
+
+	rec := adminRequest(t, NewAdminHandler(), "GET", "/", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Incorrect status. Expected 200, got %d", rec.Code)
+	}
+
+	var out []AdminLogger
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Invalid JSON response: %s", err.Error())
+	}
+	if len(out) != 2 || out[0].Name != "app" || out[1].Name != "db" {
+		t.Fatalf("Incorrect loggers listed: %v", out)
+	}
+
+	expected := AdminHandlerInfo{Name: "stdout", Type: "*verbose.StdoutHandler", MinLevel: "Debug", MaxLevel: "Fatal"}
+	if out[0].Handlers[0] != expected {
+		t.Errorf("Incorrect handler. Expected %v, got %v", expected, out[0].Handlers[0])
+	}
+	if out[1].Handlers[0].MinLevel != "" {
+		t.Error("Handler without LevelReporter shouldn't have levels")
+	}
+}
+
+func TestAdminHandlerSetLevels(t *testing.T) {
+	clearLoggers()
+	sh1 := NewStdoutHandler(false)
+	sh2 := NewStdoutHandler(false)
+	logger := New("app")
+	logger.AddHandler("stdout1", sh1)
+	logger.AddHandler("stdout2", sh2)
+	logger.AddHandler("test", &testHandler{})
+	a := NewAdminHandler()
+
+	rec := adminRequest(t, a, "PUT", "/?logger=app", `{"min_level": "warning", "max_level": "error"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Incorrect status. Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	for _, sh := range []*StdoutHandler{sh1, sh2} {
+		if sh.min != LogLevelWarning || sh.max != LogLevelError {
+			t.Errorf("Incorrect levels. Expected %d-%d, got %d-%d", LogLevelWarning, LogLevelError, sh.min, sh.max)
+		}
+	}
+
+	rec = adminRequest(t, a, "POST", "/?logger=app&handler=stdout2", `{"level": "debug"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Incorrect status. Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if sh1.min != LogLevelWarning || sh2.min != LogLevelDebug || sh2.max != LogLevelDebug {
+		t.Error("Incorrect levels after changing a single handler")
+	}
+}
+
+func TestAdminHandlerRevert(t *testing.T) {
+	clearLoggers()
+	sh := NewStdoutHandler(false)
+	sh.SetMinLevel(LogLevelError)
+	New("app").AddHandler("stdout", sh)
+	a := NewAdminHandler()
+
+	rec := adminRequest(t, a, "PUT", "/?logger=app", `{"min_level": "debug", "duration": "10ms"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Incorrect status. Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if sh.MinLevel() != LogLevelDebug {
+		t.Errorf("Incorrect min level. Expected %d, got %d", LogLevelDebug, sh.MinLevel())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		a.m.Lock()
+		pending := len(a.reverts)
+		a.m.Unlock()
+		if pending == 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if sh.MinLevel() != LogLevelError || sh.MaxLevel() != LogLevelFatal {
+		t.Errorf("Levels not reverted. Expected %d-%d, got %d-%d", LogLevelError, LogLevelFatal, sh.MinLevel(), sh.MaxLevel())
+	}
+}
+
+func TestAdminHandlerErrors(t *testing.T) {
+	clearLoggers()
+	app := New("app")
+	app.AddHandler("stdout", NewStdoutHandler(false))
+	app.AddHandler("test", &testHandler{})
+	New("db").AddHandler("test", &testHandler{})
+	a := NewAdminHandler()
+
+	tests := []struct {
+		method, url, body string
+		status            int
+	}{
+		{"DELETE", "/?logger=app", "", http.StatusMethodNotAllowed},
+		{"PUT", "/", `{"level": "debug"}`, http.StatusBadRequest},
+		{"PUT", "/?logger=nope", `{"level": "debug"}`, http.StatusNotFound},
+		{"PUT", "/?logger=app&handler=nope", `{"level": "debug"}`, http.StatusNotFound},
+		{"PUT", "/?logger=app", `{"level": "loud"}`, http.StatusBadRequest},
+		{"PUT", "/?logger=app", `{}`, http.StatusBadRequest},
+		{"PUT", "/?logger=app", `{"level": "debug", "duration": "soon"}`, http.StatusBadRequest},
+		{"PUT", "/?logger=app", `not json`, http.StatusBadRequest},
+		{"PUT", "/?logger=app&handler=test", `{"level": "debug"}`, http.StatusBadRequest},
+		{"PUT", "/?logger=db", `{"level": "debug"}`, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		rec := adminRequest(t, a, test.method, test.url, test.body)
+		if rec.Code != test.status {
+			t.Errorf("Incorrect status for %s %s %s. Expected %d, got %d", test.method, test.url, test.body, test.status, rec.Code)
+		}
+	}
+}
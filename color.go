@@ -0,0 +1,92 @@
+package verbose
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Environment variables checked by ColorModeAuto. See https://no-color.org
+// and https://force-color.org.
+const (
+	EnvNoColor    = "NO_COLOR"
+	EnvForceColor = "FORCE_COLOR"
+)
+
+// ColorMode decides when colored output is used.
+type ColorMode int
+
+// Color modes
+const (
+	// ColorModeAuto uses color if the output is a terminal. NO_COLOR disables
+	// color and FORCE_COLOR enables it regardless of the output.
+	ColorModeAuto ColorMode = iota
+	ColorModeAlways
+	ColorModeNever
+)
+
+// ParseColorMode returns the ColorMode named s, "auto", "always" or "never".
+// The boolean strings "true", "yes", "1", "false", "no" and "0" are accepted
+// for always and never.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch strings.ToLower(s) {
+	case "auto":
+		return ColorModeAuto, nil
+	case "always", "true", "yes", "1":
+		return ColorModeAlways, nil
+	case "never", "false", "no", "0":
+		return ColorModeNever, nil
+	}
+	return ColorModeAuto, fmt.Errorf("unknown color mode %q", s)
+}
+
+func (m ColorMode) String() string {
+	switch m {
+	case ColorModeAuto:
+		return "auto"
+	case ColorModeAlways:
+		return "always"
+	case ColorModeNever:
+		return "never"
+	}
+	return fmt.Sprintf("ColorMode(%d)", int(m))
+}
+
+// UseColor returns if output written to w should be colored in mode m.
+func UseColor(m ColorMode, w io.Writer) bool {
+	switch m {
+	case ColorModeAlways:
+		return true
+	case ColorModeNever:
+		return false
+	}
+
+	if os.Getenv(EnvNoColor) != "" {
+		return false
+	}
+	switch strings.ToLower(os.Getenv(EnvForceColor)) {
+	case "":
+	case "0", "false", "no":
+		return false
+	default:
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal returns if w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
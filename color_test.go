@@ -0,0 +1,71 @@
+package verbose
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseColorMode(t *testing.T) {
+	tests := map[string]ColorMode{
+		"auto":   ColorModeAuto,
+		"Always": ColorModeAlways,
+		"true":   ColorModeAlways,
+		"1":      ColorModeAlways,
+		"never":  ColorModeNever,
+		"no":     ColorModeNever,
+	}
+	for s, expected := range tests {
+		m, err := ParseColorMode(s)
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", s, err.Error())
+			continue
+		}
+		if m != expected {
+			t.Errorf("Incorrect mode for %q. Expected %s, got %s", s, expected, m)
+		}
+	}
+
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tests := []struct {
+		env      map[string]string
+		mode     ColorMode
+		expected bool
+	}{
+		{nil, ColorModeAuto, false},
+		{nil, ColorModeAlways, true},
+		{nil, ColorModeNever, false},
+		{map[string]string{EnvForceColor: "1"}, ColorModeAuto, true},
+		{map[string]string{EnvForceColor: "0"}, ColorModeAuto, false},
+		{map[string]string{EnvForceColor: "1"}, ColorModeNever, false},
+		{map[string]string{EnvNoColor: "1"}, ColorModeAlways, true},
+		{map[string]string{EnvNoColor: "1", EnvForceColor: "1"}, ColorModeAuto, false},
+	}
+
+	for _, test := range tests {
+		setEnv(t, test.env)
+		if c := UseColor(test.mode, buf); c != test.expected {
+			t.Errorf("Incorrect result for %s with %v. Expected %t, got %t", test.mode, test.env, test.expected, c)
+		}
+	}
+}
+
+func TestClassicColor(t *testing.T) {
+	clearLoggers()
+	setEnv(t, map[string]string{EnvNoColor: "1"})
+	l, _ := Classic("app", "")
+	if _, ok := l.GetHandler("stdout").(*StdoutHandler).formatter.(*LineFormatter); !ok {
+		t.Error("Incorrect stdout formatter with NO_COLOR, not LineFormatter")
+	}
+
+	setEnv(t, map[string]string{EnvForceColor: "1"})
+	l, _ = Classic("app", "")
+	if _, ok := l.GetHandler("stdout").(*StdoutHandler).formatter.(*ColoredLineFormatter); !ok {
+		t.Error("Incorrect stdout formatter with FORCE_COLOR, not ColoredLineFormatter")
+	}
+}
@@ -0,0 +1,413 @@
+package verbose
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Config describes a set of loggers and their handlers. It's used with
+// Configure and ApplyConfig. If StaticFields is set, it replaces the static
+// fields included in every entry, see SetStaticFields.
+//
+//	{
+//	    "loggers": {
+//	        "app": {
+//	            "handlers": {
+//	                "stdout": {"type": "stdout", "color": true, "min_level": "info"},
+//	                "file": {
+//	                    "type": "file",
+//	                    "path": "logs/app.log",
+//	                    "formatter": {"type": "json", "time_format": "RFC3339Nano"}
+//	                }
+//	            }
+//	        }
+//	    }
+//	}
+type Config struct {
+	Loggers      map[string]LoggerConfig `json:"loggers"`
+	StaticFields Fields                  `json:"static_fields,omitempty"`
+}
+
+// LoggerConfig describes a single logger.
+type LoggerConfig struct {
+	Handlers map[string]HandlerConfig `json:"handlers"`
+}
+
+// HandlerConfig describes a handler. Type is the name the handler type was
+// registered with. Path is used by the file handler, and the journald handler
+// as the socket path, and Color by the stdout handler. Options holds settings
+// for other handler types, such as "network" and "address" for gelf, or
+// "stderr_level" and "color_mode" for stdout. The color_mode option overrides
+// Color. Limits are set on the handler's formatter.
+type HandlerConfig struct {
+	Type      string           `json:"type"`
+	Level     string           `json:"level,omitempty"`
+	MinLevel  string           `json:"min_level,omitempty"`
+	MaxLevel  string           `json:"max_level,omitempty"`
+	Formatter *FormatterConfig `json:"formatter,omitempty"`
+	Path      string           `json:"path,omitempty"`
+	Color     bool             `json:"color,omitempty"`
+	Limits    *Limits          `json:"limits,omitempty"`
+	Options   json.RawMessage  `json:"options,omitempty"`
+}
+
+// FormatterConfig describes a formatter. Type is the name the formatter type
+// was registered with. TimeFormat is either a Go time layout or the name of
+// a layout constant in the time package such as "RFC3339" or "Kitchen".
+type FormatterConfig struct {
+	Type       string          `json:"type"`
+	TimeFormat string          `json:"time_format,omitempty"`
+	Options    json.RawMessage `json:"options,omitempty"`
+}
+
+// HandlerFactory creates a Handler from its configuration. Levels and
+// formatters are applied after the handler is created.
+type HandlerFactory func(HandlerConfig) (Handler, error)
+
+// FormatterFactory creates a Formatter from its configuration. The time
+// format is applied after the formatter is created.
+type FormatterFactory func(FormatterConfig) (Formatter, error)
+
+var (
+	handlerTypes   = make(map[string]HandlerFactory)
+	formatterTypes = make(map[string]FormatterFactory)
+	typesMutex     = sync.RWMutex{}
+)
+
+func init() {
+	RegisterHandlerType("stdout", func(c HandlerConfig) (Handler, error) {
+		var opts struct {
+			StderrLevel string `json:"stderr_level"`
+			ColorMode   string `json:"color_mode"`
+		}
+		if err := decodeOptions(c.Options, &opts); err != nil {
+			return nil, err
+		}
+		sh := NewStdoutHandler(c.Color)
+		if opts.ColorMode != "" {
+			m, err := ParseColorMode(opts.ColorMode)
+			if err != nil {
+				return nil, err
+			}
+			sh = NewStdoutHandlerWithColor(m)
+		}
+		if opts.StderrLevel != "" {
+			l, err := ParseLevel(opts.StderrLevel)
+			if err != nil {
+				return nil, err
+			}
+			sh.SetStderrLevel(l)
+		}
+		return sh, nil
+	})
+	RegisterHandlerType("file", func(c HandlerConfig) (Handler, error) {
+		if c.Path == "" {
+			return nil, errors.New("path is required")
+		}
+		return NewFileHandler(c.Path)
+	})
+	RegisterHandlerType("gelf", func(c HandlerConfig) (Handler, error) {
+		var opts struct {
+			Network string `json:"network"`
+			Address string `json:"address"`
+		}
+		if err := decodeOptions(c.Options, &opts); err != nil {
+			return nil, err
+		}
+		if opts.Network == "" {
+			opts.Network = "udp"
+		}
+		return NewGELFHandler(opts.Network, opts.Address)
+	})
+
+	RegisterFormatterType("json", func(c FormatterConfig) (Formatter, error) {
+		var opts struct {
+			StaticFieldsTopLevel bool `json:"static_fields_top_level"`
+		}
+		if err := decodeOptions(c.Options, &opts); err != nil {
+			return nil, err
+		}
+		j := NewJSONFormatter()
+		j.SetStaticFieldsTopLevel(opts.StaticFieldsTopLevel)
+		return j, nil
+	})
+	RegisterFormatterType("line", func(c FormatterConfig) (Formatter, error) {
+		f := NewLineFormatter()
+		return f, configMultiline(c, &f.multiline)
+	})
+	RegisterFormatterType("colored-line", func(c FormatterConfig) (Formatter, error) {
+		f := NewColoredLineFormatter()
+		return f, configMultiline(c, &f.multiline)
+	})
+	RegisterFormatterType("gelf", func(_ FormatterConfig) (Formatter, error) {
+		return NewGELFFormatter(), nil
+	})
+	RegisterFormatterType("pretty", func(c FormatterConfig) (Formatter, error) {
+		var opts struct {
+			Color          bool `json:"color"`
+			InlineFields   bool `json:"inline_fields"`
+			MaxValueLength *int `json:"max_value_length"`
+		}
+		if err := decodeOptions(c.Options, &opts); err != nil {
+			return nil, err
+		}
+		p := NewPrettyFormatter(opts.Color)
+		p.SetInlineFields(opts.InlineFields)
+		if opts.MaxValueLength != nil {
+			p.SetMaxValueLength(*opts.MaxValueLength)
+		}
+		return p, nil
+	})
+}
+
+// RegisterHandlerType makes a handler type available to configurations
+// under name. Registering an existing name replaces it.
+func RegisterHandlerType(name string, f HandlerFactory) {
+	typesMutex.Lock()
+	handlerTypes[name] = f
+	typesMutex.Unlock()
+}
+
+// RegisterFormatterType makes a formatter type available to configurations
+// under name. Registering an existing name replaces it.
+func RegisterFormatterType(name string, f FormatterFactory) {
+	typesMutex.Lock()
+	formatterTypes[name] = f
+	typesMutex.Unlock()
+}
+
+// Configure reads a JSON document describing loggers and applies it with
+// ApplyConfig.
+func Configure(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var c Config
+	if err := dec.Decode(&c); err != nil {
+		return fmt.Errorf("invalid configuration: %v", err)
+	}
+	return ApplyConfig(&c)
+}
+
+// ApplyConfig creates the handlers in c and adds them to their loggers.
+// Loggers are retrieved with Get so existing loggers are kept. A handler
+// replaces any handler of the same name already on the logger, the replaced
+// handler is closed. If any handler can't be created, no loggers are changed.
+func ApplyConfig(c *Config) error {
+	built := make(map[string]map[string]Handler, len(c.Loggers))
+	for ln, lc := range c.Loggers {
+		handlers := make(map[string]Handler, len(lc.Handlers))
+		for hn, hc := range lc.Handlers {
+			h, err := buildHandler(hc)
+			if err != nil {
+				for _, hs := range built {
+					closeHandlers(hs)
+				}
+				closeHandlers(handlers)
+				return fmt.Errorf("logger %q: handler %q: %v", ln, hn, err)
+			}
+			handlers[hn] = h
+		}
+		built[ln] = handlers
+	}
+
+	for ln, handlers := range built {
+		l := Get(ln)
+		for hn, h := range handlers {
+			if old := l.replaceHandler(hn, h); old != nil && old != h {
+				old.Close()
+			}
+		}
+	}
+	if c.StaticFields != nil {
+		SetStaticFields(c.StaticFields)
+	}
+	return nil
+}
+
+func closeHandlers(handlers map[string]Handler) {
+	for _, h := range handlers {
+		h.Close()
+	}
+}
+
+func buildHandler(c HandlerConfig) (Handler, error) {
+	typesMutex.RLock()
+	factory, ok := handlerTypes[c.Type]
+	typesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown handler type %q", c.Type)
+	}
+
+	min, max, err := configLevels(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var formatter Formatter
+	if c.Formatter != nil {
+		formatter, err = buildFormatter(*c.Formatter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h, err := factory(c)
+	if err != nil {
+		return nil, err
+	}
+	if formatter != nil {
+		h.SetFormatter(formatter)
+	}
+	if c.Limits != nil {
+		fh, ok := h.(interface{ getFormatter() Formatter })
+		if !ok {
+			h.Close()
+			return nil, errors.New("handler doesn't support limits")
+		}
+		l, ok := fh.getFormatter().(Limiter)
+		if !ok {
+			h.Close()
+			return nil, errors.New("formatter doesn't support limits")
+		}
+		l.SetLimits(*c.Limits)
+	}
+	setLevelRange(h, min, max)
+	return h, nil
+}
+
+// setLevelRange sets the min and max levels of h. Without LevelRangeSetter,
+// the levels are set in the order that keeps min below max at each step.
+func setLevelRange(h Handler, min, max LogLevel) {
+	if rs, ok := h.(LevelRangeSetter); ok {
+		rs.SetLevelRange(min, max)
+		return
+	}
+
+	curMin := LogLevelDebug
+	if lr, ok := h.(LevelReporter); ok {
+		curMin = lr.MinLevel()
+	}
+	if max < curMin {
+		h.SetMinLevel(min)
+		h.SetMaxLevel(max)
+	} else {
+		h.SetMaxLevel(max)
+		h.SetMinLevel(min)
+	}
+}
+
+func configLevels(c HandlerConfig) (LogLevel, LogLevel, error) {
+	min, max := LogLevelDebug, LogLevelFatal
+	if c.Level != "" {
+		if c.MinLevel != "" || c.MaxLevel != "" {
+			return 0, 0, errors.New("level can't be used with min_level or max_level")
+		}
+		l, err := ParseLevel(c.Level)
+		if err != nil {
+			return 0, 0, err
+		}
+		return l, l, nil
+	}
+
+	var err error
+	if c.MinLevel != "" {
+		if min, err = ParseLevel(c.MinLevel); err != nil {
+			return 0, 0, err
+		}
+	}
+	if c.MaxLevel != "" {
+		if max, err = ParseLevel(c.MaxLevel); err != nil {
+			return 0, 0, err
+		}
+	}
+	if min > max {
+		return 0, 0, fmt.Errorf("min_level %s is above max_level %s", min, max)
+	}
+	return min, max, nil
+}
+
+func buildFormatter(c FormatterConfig) (Formatter, error) {
+	typesMutex.RLock()
+	factory, ok := formatterTypes[c.Type]
+	typesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown formatter type %q", c.Type)
+	}
+
+	f, err := factory(c)
+	if err != nil {
+		return nil, fmt.Errorf("formatter %q: %v", c.Type, err)
+	}
+	if c.TimeFormat != "" {
+		f.SetTimeFormat(timeFormat(c.TimeFormat))
+	}
+	return f, nil
+}
+
+var timeFormats = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+}
+
+// timeFormat returns the layout named f, or f itself if it isn't a name.
+func timeFormat(f string) string {
+	if layout, ok := timeFormats[f]; ok {
+		return layout
+	}
+	return f
+}
+
+// configMultiline applies the "multiline" and "multiline_prefix" options of
+// a line formatter.
+func configMultiline(c FormatterConfig, m *multiline) error {
+	var opts struct {
+		Multiline       string  `json:"multiline"`
+		MultilinePrefix *string `json:"multiline_prefix"`
+	}
+	if err := decodeOptions(c.Options, &opts); err != nil {
+		return err
+	}
+	if opts.Multiline != "" {
+		mode, err := ParseMultilineMode(opts.Multiline)
+		if err != nil {
+			return err
+		}
+		m.SetMultilineMode(mode)
+	}
+	if opts.MultilinePrefix != nil {
+		m.SetMultilinePrefix(*opts.MultilinePrefix)
+	}
+	return nil
+}
+
+// decodeOptions decodes handler or formatter options into v. Unknown
+// options are an error.
+func decodeOptions(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid options: %v", err)
+	}
+	return nil
+}
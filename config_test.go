@@ -0,0 +1,186 @@
+package verbose
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigure(t *testing.T) {
+	clearLoggers()
+	defer cleanup()
+
+	err := Configure([]byte(`{
+		"loggers": {
+			"app": {
+				"handlers": {
+					"stdout": {
+						"type": "stdout",
+						"color": true,
+						"min_level": "warning",
+						"options": {"stderr_level": "error"}
+					},
+					"file": {
+						"type": "file",
+						"path": "test.log",
+						"level": "ERROR",
+						"formatter": {"type": "json", "time_format": "Kitchen"}
+					}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Error configuring: %s", err.Error())
+	}
+
+	logger := getLogger("app")
+	if logger == nil {
+		t.Fatal("Logger app wasn't created")
+	}
+
+	sh, ok := logger.GetHandler("stdout").(*StdoutHandler)
+	if !ok {
+		t.Fatal("Handler stdout isn't a StdoutHandler")
+	}
+	if sh.min != LogLevelWarning || sh.max != LogLevelFatal {
+		t.Errorf("Incorrect stdout levels. Expected %d-%d, got %d-%d", LogLevelWarning, LogLevelFatal, sh.min, sh.max)
+	}
+	if _, ok := sh.formatter.(*ColoredLineFormatter); !ok {
+		t.Error("Incorrect stdout formatter, not ColoredLineFormatter")
+	}
+	if !sh.split || sh.errLevel != LogLevelError {
+		t.Errorf("Incorrect stderr level. Expected %d, got %d", LogLevelError, sh.errLevel)
+	}
+
+	fh, ok := logger.GetHandler("file").(*FileHandler)
+	if !ok {
+		t.Fatal("Handler file isn't a FileHandler")
+	}
+	if fh.min != LogLevelError || fh.max != LogLevelError {
+		t.Errorf("Incorrect file levels. Expected %d-%d, got %d-%d", LogLevelError, LogLevelError, fh.min, fh.max)
+	}
+	jf, ok := fh.formatter.(*JSONFormatter)
+	if !ok {
+		t.Fatal("Incorrect file formatter, not JSONFormatter")
+	}
+	if jf.timeFormat != time.Kitchen {
+		t.Errorf("Incorrect time format. Expected %s, got %s", time.Kitchen, jf.timeFormat)
+	}
+}
+
+func TestConfigureCustomType(t *testing.T) {
+	clearLoggers()
+	RegisterHandlerType("test", func(c HandlerConfig) (Handler, error) {
+		var opts struct {
+			Level string `json:"level"`
+		}
+		if err := decodeOptions(c.Options, &opts); err != nil {
+			return nil, err
+		}
+		l, err := ParseLevel(opts.Level)
+		return &testHandler{level: l}, err
+	})
+
+	err := ApplyConfig(&Config{
+		Loggers: map[string]LoggerConfig{
+			"app": {Handlers: map[string]HandlerConfig{
+				"test": {Type: "test", Options: []byte(`{"level": "notice"}`)},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error configuring: %s", err.Error())
+	}
+
+	th, ok := getLogger("app").GetHandler("test").(*testHandler)
+	if !ok {
+		t.Fatal("Handler test isn't a testHandler")
+	}
+	if th.level != LogLevelNotice {
+		t.Errorf("Incorrect option. Expected %d, got %d", LogLevelNotice, th.level)
+	}
+}
+
+func TestConfigureTraceLevel(t *testing.T) {
+	clearLoggers()
+	err := Configure([]byte(`{"loggers": {"app": {"handlers": {
+		"trace": {"type": "stdout", "level": "trace"},
+		"low": {"type": "stdout", "min_level": "trace", "max_level": "trace"}
+	}}}}`))
+	if err != nil {
+		t.Fatalf("Error configuring: %s", err.Error())
+	}
+
+	for _, hn := range []string{"trace", "low"} {
+		sh := getLogger("app").GetHandler(hn).(*StdoutHandler)
+		if sh.MinLevel() != LogLevelTrace || sh.MaxLevel() != LogLevelTrace {
+			t.Errorf("Incorrect %s levels. Expected %d-%d, got %d-%d", hn, LogLevelTrace, LogLevelTrace, sh.MinLevel(), sh.MaxLevel())
+		}
+	}
+
+	// Only the Handler methods, without LevelRangeSetter
+	sh := NewStdoutHandler(false)
+	setLevelRange(struct{ Handler }{sh}, LogLevelTrace, LogLevelTrace)
+	if sh.MinLevel() != LogLevelTrace || sh.MaxLevel() != LogLevelTrace {
+		t.Errorf("Incorrect levels. Expected %d-%d, got %d-%d", LogLevelTrace, LogLevelTrace, sh.MinLevel(), sh.MaxLevel())
+	}
+}
+
+type closingHandler struct {
+	testHandler
+	closed bool
+}
+
+func (c *closingHandler) Close() { c.closed = true }
+
+func TestConfigureReplacesHandlers(t *testing.T) {
+	clearLoggers()
+	RegisterHandlerType("closing", func(c HandlerConfig) (Handler, error) {
+		return &closingHandler{}, nil
+	})
+
+	config := []byte(`{"loggers": {"app": {"handlers": {"h": {"type": "closing"}}}}}`)
+	if err := Configure(config); err != nil {
+		t.Fatalf("Error configuring: %s", err.Error())
+	}
+	first := getLogger("app").GetHandler("h").(*closingHandler)
+	if err := Configure(config); err != nil {
+		t.Fatalf("Error configuring: %s", err.Error())
+	}
+	if !first.closed {
+		t.Error("Replaced handler wasn't closed")
+	}
+	if getLogger("app").GetHandler("h").(*closingHandler).closed {
+		t.Error("New handler was closed")
+	}
+}
+
+func TestConfigureErrors(t *testing.T) {
+	clearLoggers()
+	tests := map[string]string{
+		`{"loggers": {"app": {"handlers": {"h": {"type": "nope"}}}}}`:                                                                    `logger "app": handler "h": unknown handler type "nope"`,
+		`{"loggers": {"app": {"handlers": {"h": {"type": "stdout", "level": "loud"}}}}}`:                                                 `invalid log level "loud"`,
+		`{"loggers": {"app": {"handlers": {"h": {"type": "stdout", "formatter": {"type": "xml"}}}}}}`:                                    `unknown formatter type "xml"`,
+		`{"loggers": {"app": {"handlers": {"h": {"type": "stdout", "min_level": "error", "max_level": "info"}}}}}`:                       `min_level Error is above max_level Info`,
+		`{"loggers": {"app": {"handlers": {"h": {"type": "file"}}}}}`:                                                                    `path is required`,
+		`{"loggers": {"app": {"handlers": {"h": {"type": "stdout", "options": {"color_mode": "maybe"}}}}}}`:                              `unknown color mode "maybe"`,
+		`{"loggers": {"app": {"handlers": {"h": {"type": "stdout", "formatter": {"type": "line", "options": {"multiline": "wrap"}}}}}}}`: `unknown multiline mode "wrap"`,
+		`{"loggers": {"app": {"handles": {}}}}`:                                                                                          `unknown field "handles"`,
+	}
+
+	for config, expected := range tests {
+		err := Configure([]byte(config))
+		if err == nil {
+			t.Errorf("Expected error for %s", config)
+			continue
+		}
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Incorrect error. Expected `%s`, got `%s`", expected, err.Error())
+		}
+	}
+
+	if getLogger("app") != nil {
+		t.Error("Logger created from invalid configuration")
+	}
+}
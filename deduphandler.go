@@ -0,0 +1,168 @@
+package verbose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupRepeatedKey is the field holding the repeat count in summary entries
+const DedupRepeatedKey = "repeated"
+
+// DedupHandler collapses repeated entries. The first entry is written to the
+// wrapped handler immediately, identical entries logged within the window
+// after it are dropped. When the window ends a single summary entry,
+// "message repeated N times: ...", is written with the count in the
+// "repeated" field.
+type DedupHandler struct {
+	levelRange
+	handler Handler
+	window  time.Duration
+	keyFunc func(*Entry) string
+	pending map[string]*dedupState
+	m       sync.Mutex
+	writeM  sync.Mutex
+}
+
+type dedupState struct {
+	entry *Entry
+	last  time.Time
+	count int
+	timer *time.Timer
+}
+
+// DedupByMessage considers entries identical if they have the same logger,
+// level and message. This is the default.
+func DedupByMessage(e *Entry) string {
+	return fmt.Sprintf("%s\x00%d\x00%s", e.Logger.Name(), e.Level, e.Message)
+}
+
+// DedupByMessageAndFields considers entries identical if they have the same
+// logger, level, message and fields.
+func DedupByMessageAndFields(e *Entry) string {
+	keys := make([]string, 0, len(e.Data))
+	for k := range e.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := &strings.Builder{}
+	b.WriteString(DedupByMessage(e))
+	for _, k := range keys {
+		fmt.Fprintf(b, "\x00%s=%v", k, resolveValue(e.Data[k]))
+	}
+	return b.String()
+}
+
+// NewDedupHandler creates a DedupHandler wrapping h which collapses
+// identical entries logged within window of each other.
+func NewDedupHandler(h Handler, window time.Duration) *DedupHandler {
+	return &DedupHandler{
+		levelRange: newLevelRange(),
+		handler:    h,
+		window:     window,
+		keyFunc:    DedupByMessage,
+		pending:    make(map[string]*dedupState),
+	}
+}
+
+// SetKeyFunc sets the function used to decide if entries are identical.
+// Entries with the same key are collapsed.
+func (d *DedupHandler) SetKeyFunc(f func(*Entry) string) {
+	if f == nil {
+		f = DedupByMessage
+	}
+	d.m.Lock()
+	d.keyFunc = f
+	d.m.Unlock()
+}
+
+// SetWindow sets how long repeated entries are collapsed for.
+func (d *DedupHandler) SetWindow(w time.Duration) {
+	d.m.Lock()
+	d.window = w
+	d.m.Unlock()
+}
+
+// SetFormatter sets the formatter of the wrapped handler.
+func (d *DedupHandler) SetFormatter(f Formatter) {
+	d.handler.SetFormatter(f)
+}
+
+// WriteLog writes the entry to the wrapped handler unless it's a repeat of
+// an entry seen within the window.
+func (d *DedupHandler) WriteLog(e *Entry) {
+	d.m.Lock()
+	key := d.keyFunc(e)
+	if st, ok := d.pending[key]; ok {
+		st.count++
+		st.last = e.Timestamp
+		d.m.Unlock()
+		return
+	}
+
+	st := &dedupState{entry: e.Clone()}
+	st.timer = time.AfterFunc(d.window, func() { d.expire(key, st) })
+	d.pending[key] = st
+	d.m.Unlock()
+
+	d.write(e)
+}
+
+func (d *DedupHandler) expire(key string, st *dedupState) {
+	d.m.Lock()
+	if d.pending[key] != st {
+		d.m.Unlock()
+		return
+	}
+	delete(d.pending, key)
+	d.m.Unlock()
+
+	d.summarize(st)
+}
+
+// summarize writes the summary entry for st if any entries were dropped.
+func (d *DedupHandler) summarize(st *dedupState) {
+	if st.count == 0 {
+		return
+	}
+
+	e := st.entry.Clone()
+	e.Message = fmt.Sprintf("message repeated %d times: %s", st.count, e.Message)
+	e.Timestamp = st.last
+	e.Data[DedupRepeatedKey] = st.count
+	d.write(e)
+}
+
+func (d *DedupHandler) write(e *Entry) {
+	if !d.handler.Handles(e.Level) {
+		return
+	}
+	d.writeM.Lock()
+	d.handler.WriteLog(e)
+	d.writeM.Unlock()
+}
+
+// Flush writes summaries for all pending repeated entries and starts new
+// windows.
+func (d *DedupHandler) Flush() {
+	d.m.Lock()
+	pending := d.pending
+	d.pending = make(map[string]*dedupState)
+	for _, st := range pending {
+		st.timer.Stop()
+	}
+	d.m.Unlock()
+
+	for _, st := range pending {
+		d.summarize(st)
+	}
+}
+
+// Close flushes pending summaries and closes the wrapped handler.
+func (d *DedupHandler) Close() {
+	d.Flush()
+	d.handler.Close()
+}
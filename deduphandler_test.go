@@ -0,0 +1,75 @@
+package verbose
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupHandler(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	dh := NewDedupHandler(rec, time.Hour)
+	logger := New("logger")
+	logger.AddHandler("dedup", dh)
+
+	for i := 0; i < 5; i++ {
+		logger.Error("connection refused")
+	}
+	logger.Warning("connection refused")
+	logger.Error("something else")
+	checkMessages(t, []string{"connection refused", "connection refused", "something else"}, rec.messages())
+
+	dh.Flush()
+	checkMessages(t, []string{
+		"connection refused",
+		"connection refused",
+		"something else",
+		"message repeated 4 times: connection refused",
+	}, rec.messages())
+
+	summary := rec.entries[3]
+	if summary.Level != LogLevelError {
+		t.Errorf("Incorrect summary level. Expected %d, got %d", LogLevelError, summary.Level)
+	}
+	if summary.Data[DedupRepeatedKey] != 4 {
+		t.Errorf("Incorrect repeat count. Expected 4, got %v", summary.Data[DedupRepeatedKey])
+	}
+
+	logger.Error("connection refused")
+	if len(rec.messages()) != 5 {
+		t.Error("Entry after flush wasn't written")
+	}
+}
+
+func TestDedupHandlerWindow(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("dedup", NewDedupHandler(rec, 10*time.Millisecond))
+
+	logger.Error("connection refused")
+	logger.Error("connection refused")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(rec.messages()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	checkMessages(t, []string{
+		"connection refused",
+		"message repeated 1 times: connection refused",
+	}, rec.messages())
+}
+
+func TestDedupHandlerKeyFunc(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	dh := NewDedupHandler(rec, time.Hour)
+	dh.SetKeyFunc(DedupByMessageAndFields)
+	logger := New("logger")
+	logger.AddHandler("dedup", dh)
+
+	logger.WithField("host", "db1").Error("connection refused")
+	logger.WithField("host", "db2").Error("connection refused")
+	logger.WithField("host", "db1").Error("connection refused")
+	checkMessages(t, []string{"connection refused", "connection refused"}, rec.messages())
+}
@@ -14,6 +14,7 @@ type Entry struct {
 	Logger    *Logger
 	Message   string
 	Data      Fields
+	static    Fields // Static fields added to Data
 }
 
 // NewEntry creates a new, empty Entry
@@ -41,19 +42,69 @@ func (e *Entry) WithFields(fields Fields) *Entry {
 	return &Entry{Logger: e.Logger, Data: data}
 }
 
+// Clone returns a copy of the Entry. Handlers that keep entries after WriteLog
+// returns should store a clone since the original may be reused.
+func (e *Entry) Clone() *Entry {
+	c := *e
+	c.Data = make(Fields, len(e.Data))
+	for k, v := range e.Data {
+		c.Data[k] = v
+	}
+	return &c
+}
+
+// Log logs a message at level. It's meant for custom levels or when the
+// level is chosen at runtime. Unlike Fatal, it doesn't exit.
+func (e *Entry) Log(level LogLevel, v ...interface{}) {
+	e.log(level, fmt.Sprint(v...))
+}
+
+// Logf logs a formatted message at level. Unlike Fatalf, it doesn't exit.
+func (e *Entry) Logf(level LogLevel, m string, v ...interface{}) {
+	e.log(level, fmt.Sprintf(m, v...))
+}
+
+// Logln logs a message at level with spaces between operands. Unlike Fatalln,
+// it doesn't exit.
+func (e *Entry) Logln(level LogLevel, v ...interface{}) {
+	e.log(level, e.sprintlnn(v...))
+}
+
 // Log is the generic function to log a message with the handlers.
 // All other logging functions are simply wrappers around this.
 func (e *Entry) log(level LogLevel, msg string) {
 	e.Logger.m.RLock()
+	defer e.Logger.m.RUnlock()
 	e.Level = level
 	e.Message = msg
-	e.Timestamp = time.Now()
+
+	if e.Logger.sampler != nil && !e.Logger.sampler.sample(e) {
+		return
+	}
+
+	e.Timestamp = e.Logger.now()
+	e.process()
+}
+
+// process adds the static fields to the entry, redacts it and writes it.
+// The logger lock must be held.
+func (e *Entry) process() {
+	e = e.withStaticFields()
+	if e.Logger.redaction != nil {
+		e.Logger.redaction.Apply(e).write()
+		return
+	}
+	e.write()
+}
+
+// write gives the entry to all handlers that handle its level. The logger
+// lock must be held.
+func (e *Entry) write() {
 	for _, h := range e.Logger.handlers {
-		if h.Handles(level) {
+		if h.Handles(e.Level) {
 			h.WriteLog(e)
 		}
 	}
-	e.Logger.m.RUnlock()
 }
 
 // sprintlnn take from Logrus: github.com/Sirupsen/logrus entry.go
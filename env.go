@@ -0,0 +1,155 @@
+package verbose
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Environment variables read by ConfigureFromEnv
+const (
+	EnvLevel  = "VERBOSE_LEVEL"
+	EnvLevels = "VERBOSE_LEVELS"
+	EnvFormat = "VERBOSE_FORMAT"
+	EnvColor  = "VERBOSE_COLOR"
+)
+
+// ConfigureFromEnv applies environment variables to the StdoutHandlers and
+// FileHandlers of all registered loggers. Loggers created afterwards aren't
+// affected so it should be called once the loggers are set up. Unset
+// variables are ignored.
+//
+//	VERBOSE_LEVEL=info                            Minimum level of all handlers
+//	VERBOSE_LEVELS="app.db=debug,app.http=warning" Minimum level per logger
+//	VERBOSE_FORMAT=json                           Formatter, "json" or "line"
+//	VERBOSE_COLOR=auto                            Stdout color, "auto", "always" or "never"
+//
+// The auto color mode also respects NO_COLOR and FORCE_COLOR, see
+// ColorModeAuto.
+func ConfigureFromEnv() error {
+	c, err := readEnvConfig()
+	if err != nil {
+		return err
+	}
+
+	loggersMutex.RLock()
+	ls := make([]*Logger, 0, len(loggers))
+	for _, l := range loggers {
+		ls = append(ls, l)
+	}
+	loggersMutex.RUnlock()
+
+	for _, l := range ls {
+		c.apply(l)
+	}
+	return nil
+}
+
+type envConfig struct {
+	level    LogLevel
+	hasLevel bool
+	levels   map[string]LogLevel
+	format   string
+	color    ColorMode
+	hasColor bool
+}
+
+func readEnvConfig() (*envConfig, error) {
+	c := &envConfig{levels: make(map[string]LogLevel)}
+
+	if v := os.Getenv(EnvLevel); v != "" {
+		l, err := ParseLevel(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", EnvLevel, err)
+		}
+		c.level = l
+		c.hasLevel = true
+	}
+
+	if v := os.Getenv(EnvLevels); v != "" {
+		for _, pair := range strings.Split(v, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			i := strings.LastIndexByte(pair, '=')
+			if i < 1 {
+				return nil, fmt.Errorf("%s: expected logger=level, got %q", EnvLevels, pair)
+			}
+			l, err := ParseLevel(strings.TrimSpace(pair[i+1:]))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", EnvLevels, err)
+			}
+			c.levels[strings.TrimSpace(pair[:i])] = l
+		}
+	}
+
+	c.format = strings.ToLower(os.Getenv(EnvFormat))
+	switch c.format {
+	case "", "json", "line":
+	default:
+		return nil, fmt.Errorf("%s: unknown format %q", EnvFormat, c.format)
+	}
+
+	if v := os.Getenv(EnvColor); v != "" {
+		m, err := ParseColorMode(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", EnvColor, err)
+		}
+		c.color = m
+		c.hasColor = true
+	}
+	return c, nil
+}
+
+func (c *envConfig) apply(l *Logger) {
+	level, hasLevel := c.levels[l.Name()]
+	if !hasLevel {
+		level, hasLevel = c.level, c.hasLevel
+	}
+
+	l.m.RLock()
+	defer l.m.RUnlock()
+
+	for _, h := range l.handlers {
+		switch h := h.(type) {
+		case *StdoutHandler:
+			if f := c.stdoutFormatter(h); f != nil {
+				h.SetFormatter(f)
+			}
+		case *FileHandler:
+			if c.format == "json" {
+				h.SetFormatter(NewJSONFormatter())
+			} else if c.format == "line" {
+				h.SetFormatter(NewLineFormatter())
+			}
+		default:
+			continue
+		}
+
+		if hasLevel {
+			h.SetMinLevel(level)
+		}
+	}
+}
+
+// stdoutFormatter returns the formatter h should use, or nil if it
+// shouldn't change.
+func (c *envConfig) stdoutFormatter(h *StdoutHandler) Formatter {
+	switch {
+	case c.format == "json":
+		return NewJSONFormatter()
+	case c.hasColor:
+		if UseColor(c.color, h.out) {
+			return NewColoredLineFormatter()
+		}
+		return NewLineFormatter()
+	case c.format == "line":
+		// Only the format was given, keep the current color setting
+		if _, ok := h.getFormatter().(*ColoredLineFormatter); ok {
+			return nil
+		}
+		return NewLineFormatter()
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package verbose
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func setEnv(t *testing.T, vars map[string]string) {
+	for _, k := range []string{EnvLevel, EnvLevels, EnvFormat, EnvColor, EnvNoColor, EnvForceColor} {
+		k := k
+		old, ok := os.LookupEnv(k)
+		os.Setenv(k, vars[k])
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func TestConfigureFromEnv(t *testing.T) {
+	clearLoggers()
+	defer cleanup()
+	setEnv(t, map[string]string{
+		EnvLevel:  "warning",
+		EnvLevels: "app.db=debug, app.http = error",
+		EnvFormat: "json",
+	})
+
+	app := New("app")
+	app.AddHandler("stdout", NewStdoutHandler(true))
+	db := New("app.db")
+	fh, err := NewFileHandler(testLogFile)
+	if err != nil {
+		t.Fatalf("Error making file handler: %s", err.Error())
+	}
+	fh.SetMinLevel(LogLevelInfo)
+	db.AddHandler("file", fh)
+	http := New("app.http")
+	http.AddHandler("stdout", NewStdoutHandler(false))
+
+	if err := ConfigureFromEnv(); err != nil {
+		t.Fatalf("Error configuring from environment: %s", err.Error())
+	}
+
+	levels := map[*Logger]LogLevel{
+		app:  LogLevelWarning,
+		db:   LogLevelDebug,
+		http: LogLevelError,
+	}
+	for l, level := range levels {
+		for _, h := range l.handlers {
+			if !h.Handles(level) || h.Handles(level-1) {
+				t.Errorf("Incorrect min level for logger %s. Expected %s", l.Name(), level)
+			}
+		}
+	}
+
+	if _, ok := fh.formatter.(*JSONFormatter); !ok {
+		t.Error("Incorrect file formatter, not JSONFormatter")
+	}
+	if _, ok := app.GetHandler("stdout").(*StdoutHandler).formatter.(*JSONFormatter); !ok {
+		t.Error("Incorrect stdout formatter, not JSONFormatter")
+	}
+}
+
+func TestConfigureFromEnvColor(t *testing.T) {
+	clearLoggers()
+	setEnv(t, map[string]string{EnvColor: "auto"})
+
+	sh := NewStdoutHandler(true)
+	sh.out = &bytes.Buffer{}
+	New("app").AddHandler("stdout", sh)
+
+	if err := ConfigureFromEnv(); err != nil {
+		t.Fatalf("Error configuring from environment: %s", err.Error())
+	}
+	if _, ok := sh.formatter.(*LineFormatter); !ok {
+		t.Error("Incorrect stdout formatter, not LineFormatter")
+	}
+
+	setEnv(t, map[string]string{EnvColor: "always", EnvFormat: "line"})
+	if err := ConfigureFromEnv(); err != nil {
+		t.Fatalf("Error configuring from environment: %s", err.Error())
+	}
+	if _, ok := sh.formatter.(*ColoredLineFormatter); !ok {
+		t.Error("Incorrect stdout formatter, not ColoredLineFormatter")
+	}
+}
+
+func TestConfigureFromEnvErrors(t *testing.T) {
+	tests := []map[string]string{
+		{EnvLevel: "loud"},
+		{EnvLevels: "app.db"},
+		{EnvLevels: "app.db=loud"},
+		{EnvFormat: "xml"},
+		{EnvColor: "sometimes"},
+	}
+
+	for _, vars := range tests {
+		setEnv(t, vars)
+		err := ConfigureFromEnv()
+		if err == nil {
+			t.Errorf("Expected error for %v", vars)
+			continue
+		}
+		for k := range vars {
+			if !strings.HasPrefix(err.Error(), k) {
+				t.Errorf("Error doesn't name the variable %s: %s", k, err.Error())
+			}
+		}
+	}
+}
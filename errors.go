@@ -0,0 +1,152 @@
+package verbose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorKey is the field key of errors added with WithError.
+const ErrorKey = "error"
+
+// maxErrorDepth limits how deep error chains are followed.
+const maxErrorDepth = 32
+
+// WithError adds err to the Entry as the ErrorKey field. Formatters render
+// error fields with their concrete type, the chain of wrapped errors and any
+// stack trace carried by the error.
+func (e *Entry) WithError(err error) *Entry {
+	return e.WithField(ErrorKey, err)
+}
+
+// WithError creates an Entry with err as the ErrorKey field.
+func (l *Logger) WithError(err error) *Entry {
+	return NewEntry(l).WithError(err)
+}
+
+// errorInfo describes an error and the errors it wraps.
+type errorInfo struct {
+	Message string      `json:"message"`
+	Type    string      `json:"type"`
+	Stack   string      `json:"stack,omitempty"`
+	Causes  []errorInfo `json:"causes,omitempty"`
+}
+
+// describeError returns the description of err. Only the first stack trace
+// found in the chain is kept since it usually covers the errors it wraps.
+func describeError(err error) errorInfo {
+	if t, ok := err.(*truncatedError); ok {
+		return t.info
+	}
+	info := describeCauses(err, 0)
+	info.Stack = findStack(err, 0)
+	return info
+}
+
+func findStack(err error, depth int) string {
+	if s := errorStack(err); s != "" || depth >= maxErrorDepth {
+		return s
+	}
+	for _, cause := range unwrapError(err) {
+		if s := findStack(cause, depth+1); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func describeCauses(err error, depth int) errorInfo {
+	if t, ok := err.(*truncatedError); ok {
+		return t.info
+	}
+	info := errorInfo{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+	}
+	if depth >= maxErrorDepth {
+		return info
+	}
+	for _, cause := range unwrapError(err) {
+		info.Causes = append(info.Causes, describeCauses(cause, depth+1))
+	}
+	return info
+}
+
+// unwrapError returns the errors wrapped by err, either with Unwrap() error
+// or Unwrap() []error as returned by errors.Join.
+func unwrapError(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	case interface{ Unwrap() []error }:
+		var causes []error
+		for _, cause := range u.Unwrap() {
+			if cause != nil {
+				causes = append(causes, cause)
+			}
+		}
+		return causes
+	}
+	return nil
+}
+
+// errorStack returns the stack trace carried by err. Errors with stack
+// traces, like those of github.com/pkg/errors, print them with %+v after the
+// message.
+func errorStack(err error) string {
+	if _, ok := err.(fmt.Formatter); !ok {
+		return ""
+	}
+	msg := err.Error()
+	detailed := fmt.Sprintf("%+v", err)
+	if detailed == msg {
+		return ""
+	}
+	return strings.Trim(strings.TrimPrefix(detailed, msg), "\n")
+}
+
+// types returns the types of the error and the errors it wraps.
+func (info errorInfo) types() []string {
+	types := []string{info.Type}
+	for _, cause := range info.Causes {
+		types = append(types, cause.types()...)
+	}
+	return types
+}
+
+// errorText returns err on a single line with the types of its chain, for
+// line formats.
+func errorText(err error) string {
+	return fmt.Sprintf("%s [%s]", err.Error(), strings.Join(describeCauses(err, 0).types(), " <- "))
+}
+
+// errorDetail returns a multi-line description of err with its type, causes
+// and stack trace.
+func errorDetail(err error) string {
+	info := describeError(err)
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s\ntype: %s", info.Message, info.Type)
+	writeCauses(b, info.Causes)
+	if info.Stack != "" {
+		b.WriteString("\nstack:\n")
+		b.WriteString(info.Stack)
+	}
+	return b.String()
+}
+
+func writeCauses(b *strings.Builder, causes []errorInfo) {
+	for _, cause := range causes {
+		fmt.Fprintf(b, "\ncaused by: %s (%s)", cause.Message, cause.Type)
+		writeCauses(b, cause.Causes)
+	}
+}
+
+// fieldString returns the string form of field value v for text formats.
+func fieldString(v interface{}) string {
+	v = resolveValue(v)
+	if err, ok := v.(error); ok {
+		return errorText(err)
+	}
+	return fmt.Sprintf("%v", v)
+}
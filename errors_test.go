@@ -0,0 +1,116 @@
+package verbose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stackError prints a fake stack trace with %+v like github.com/pkg/errors
+type stackError struct {
+	msg string
+}
+
+func (s *stackError) Error() string { return s.msg }
+
+func (s *stackError) Format(f fmt.State, verb rune) {
+	io.WriteString(f, s.msg)
+	if verb == 'v' && f.Flag('+') {
+		io.WriteString(f, "\nmain.main\n\tmain.go:10")
+	}
+}
+
+// multiError wraps several errors like errors.Join
+type multiError []error
+
+func (m multiError) Error() string   { return "multiple errors" }
+func (m multiError) Unwrap() []error { return m }
+
+func newErrorEntry(err error) *Entry {
+	e := NewEntry(&Logger{name: "logger"}).WithError(err)
+	e.Level = LogLevelError
+	e.Message = "Failed"
+	e.Timestamp = time.Now()
+	return e
+}
+
+func TestWithError(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+
+	err := errors.New("boom")
+	logger.WithError(err).Error("Failed")
+	logger.WithField("id", 1).WithError(err).Error("Failed")
+	for _, e := range rec.entries {
+		if e.Data[ErrorKey] != err {
+			t.Errorf("Error not added. Got %v", e.Data)
+		}
+	}
+	if rec.entries[1].Data["id"] != 1 {
+		t.Error("WithError dropped existing fields")
+	}
+}
+
+func TestJSONFormatterError(t *testing.T) {
+	cause := &stackError{msg: "disk full"}
+	err := fmt.Errorf("write config: %w", multiError{cause, os.ErrClosed})
+
+	var out struct {
+		Data map[string]errorInfo `json:"data"`
+	}
+	if jerr := json.Unmarshal(NewJSONFormatter().FormatByte(newErrorEntry(err)), &out); jerr != nil {
+		t.Fatalf("Invalid JSON: %s", jerr.Error())
+	}
+
+	info := out.Data[ErrorKey]
+	if info.Message != "write config: multiple errors" || info.Type != "*fmt.wrapError" {
+		t.Errorf("Incorrect error. Got %+v", info)
+	}
+	if len(info.Causes) != 1 || info.Causes[0].Type != "verbose.multiError" {
+		t.Fatalf("Incorrect cause. Got %+v", info.Causes)
+	}
+	joined := info.Causes[0].Causes
+	if len(joined) != 2 || joined[0].Message != "disk full" || joined[1].Message != os.ErrClosed.Error() {
+		t.Errorf("Incorrect joined errors. Got %+v", joined)
+	}
+
+	if jerr := json.Unmarshal(NewJSONFormatter().FormatByte(newErrorEntry(cause)), &out); jerr != nil {
+		t.Fatalf("Invalid JSON: %s", jerr.Error())
+	}
+	if out.Data[ErrorKey].Stack != "main.main\n\tmain.go:10" {
+		t.Errorf("Incorrect stack. Got %q", out.Data[ErrorKey].Stack)
+	}
+}
+
+func TestLineFormatterError(t *testing.T) {
+	err := fmt.Errorf("read config: %w", os.ErrNotExist)
+	result := NewLineFormatter().Format(newErrorEntry(err))
+	expected := `"error": "read config: file does not exist [*fmt.wrapError <- *errors.errorString]"`
+	if !strings.Contains(result, expected) {
+		t.Errorf("Incorrect error field. Expected `%s` in `%s`", expected, result)
+	}
+}
+
+func TestPrettyFormatterError(t *testing.T) {
+	err := fmt.Errorf("read config: %w", &stackError{msg: "disk full"})
+	result := NewPrettyFormatter(false).Format(newErrorEntry(err))
+	expected := strings.Join([]string{
+		"    error: read config: disk full",
+		"           type: *fmt.wrapError",
+		"           caused by: disk full (*verbose.stackError)",
+		"           stack:",
+		"           main.main",
+		"           \tmain.go:10",
+		"",
+	}, "\n")
+	if !strings.HasSuffix(result, expected) {
+		t.Errorf("Incorrect error field. Expected suffix:\n%s\nGot:\n%s", expected, result)
+	}
+}
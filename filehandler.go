@@ -11,12 +11,11 @@ import (
 
 // FileHandler writes log messages to a file to a directory
 type FileHandler struct {
-	min       LogLevel
-	max       LogLevel
-	path      string
-	separate  bool
-	formatter Formatter
-	m         sync.Mutex
+	levelRange
+	formatterHolder
+	path     string
+	separate bool
+	m        sync.Mutex
 }
 
 // NewFileHandler takes the path and returns a FileHandler. If the path exists,
@@ -28,11 +27,9 @@ func NewFileHandler(path string) (*FileHandler, error) {
 	path, _ = filepath.Abs(path)
 
 	f := &FileHandler{
-		min:       LogLevelDebug,
-		max:       LogLevelFatal,
-		path:      path,
-		formatter: NewLineFormatter(),
-		m:         sync.Mutex{},
+		levelRange:      newLevelRange(),
+		formatterHolder: formatterHolder{formatter: NewLineFormatter()},
+		path:            path,
 	}
 
 	// Determine of the path is a file or directory
@@ -73,39 +70,6 @@ func NewFileHandler(path string) (*FileHandler, error) {
 	return f, nil
 }
 
-// SetLevel will set both the minimum and maximum log levels to l. This makes
-// the handler only respond to the single level l.
-func (f *FileHandler) SetLevel(l LogLevel) {
-	f.min = l
-	f.max = l
-}
-
-// SetMinLevel will set the minimum log level the handler will handle.
-func (f *FileHandler) SetMinLevel(l LogLevel) {
-	if l > f.max {
-		return
-	}
-	f.min = l
-}
-
-// SetMaxLevel will set the maximum log level the handler will handle.
-func (f *FileHandler) SetMaxLevel(l LogLevel) {
-	if l < f.min {
-		return
-	}
-	f.max = l
-}
-
-// SetFormatter gives FileHandler a formatter for log messages.
-func (f *FileHandler) SetFormatter(fo Formatter) {
-	f.formatter = fo
-}
-
-// Handles returns whether the handler handles log level l.
-func (f *FileHandler) Handles(l LogLevel) bool {
-	return (f.min <= l && l <= f.max)
-}
-
 // WriteLog will write the log message to a file.
 func (f *FileHandler) WriteLog(e *Entry) {
 	var logfile string
@@ -125,7 +89,7 @@ func (f *FileHandler) WriteLog(e *Entry) {
 	}
 	defer file.Close()
 
-	_, err = file.Write(f.formatter.FormatByte(e))
+	_, err = file.Write(f.getFormatter().FormatByte(e))
 	if err != nil {
 		fmt.Printf("Error writing to log file: %v\n", err)
 	}
@@ -3,6 +3,7 @@ package verbose
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -71,6 +72,15 @@ func TestFileHandlerLevelSetting(t *testing.T) {
 	if !fh.Handles(LogLevelCritical) {
 		t.Errorf("Incorrect Handles result. Expected true, got %t", fh.Handles(LogLevelCritical))
 	}
+
+	fh.SetLevelRange(LogLevelDebug, LogLevelError)
+	if fh.min != LogLevelDebug || fh.max != LogLevelError {
+		t.Errorf("Level range not set correctly. Expected %d-%d, got %d-%d", LogLevelDebug, LogLevelError, fh.min, fh.max)
+	}
+	fh.SetLevelRange(LogLevelFatal, LogLevelInfo)
+	if fh.min != LogLevelDebug || fh.max != LogLevelError {
+		t.Errorf("Invalid level range was set. Got %d-%d", fh.min, fh.max)
+	}
 }
 
 func TestFileHandlerWriteLog(t *testing.T) {
@@ -108,3 +118,39 @@ func TestFileHandlerWriteLog(t *testing.T) {
 		t.Errorf("Incorrect log file size. Expected 55, got %d", stat.Size())
 	}
 }
+
+// TestFileHandlerConcurrentSettings changes the levels and formatter while
+// logging. It's meant to be run with the race detector.
+func TestFileHandlerConcurrentSettings(t *testing.T) {
+	defer cleanup()
+	clearLoggers()
+	fh, err := NewFileHandler(testLogFile)
+	if err != nil {
+		t.Fatalf("Error making file handler: %s", err.Error())
+	}
+	logger := New("logger")
+	logger.AddHandler("file", fh)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			logger.Error("Concurrent")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			fh.SetLevel(LogLevelError)
+			fh.SetMaxLevel(LogLevelFatal)
+			fh.SetFormatter(NewJSONFormatter())
+			fh.SetFormatter(NewLineFormatter())
+		}
+	}()
+	wg.Wait()
+
+	if fh.MinLevel() != LogLevelError || fh.MaxLevel() != LogLevelFatal {
+		t.Errorf("Incorrect levels. Expected %d-%d, got %d-%d", LogLevelError, LogLevelFatal, fh.MinLevel(), fh.MaxLevel())
+	}
+}
@@ -2,7 +2,9 @@ package verbose
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 )
@@ -14,7 +16,9 @@ type Formatter interface {
 }
 
 type JSONFormatter struct {
-	timeFormat string
+	limiter
+	timeFormat     string
+	staticTopLevel bool
 }
 
 func NewJSONFormatter() *JSONFormatter {
@@ -28,16 +32,34 @@ func (j *JSONFormatter) Format(e *Entry) string {
 }
 
 func (j *JSONFormatter) FormatByte(e *Entry) []byte {
+	return j.formatStructured(e, j.formatByte)
+}
+
+func (j *JSONFormatter) formatByte(e *Entry) []byte {
 	buf := bytes.Buffer{}
 	buf.WriteByte('{')
-	buf.WriteString(fmt.Sprintf(`"timestamp":"%s",`, e.Timestamp.Format(j.timeFormat)))
-	buf.WriteString(fmt.Sprintf(`"level":"%s",`, strings.ToUpper(e.Level.String())))
-	buf.WriteString(fmt.Sprintf(`"logger":"%s",`, e.Logger.Name()))
-	buf.WriteString(fmt.Sprintf(`"message":"%s",`, e.Message))
+	buf.WriteString(fmt.Sprintf(`"timestamp":%s,`, jsonString(e.Timestamp.Format(j.timeFormat))))
+	buf.WriteString(fmt.Sprintf(`"level":%s,`, jsonString(strings.ToUpper(e.Level.String()))))
+	buf.WriteString(fmt.Sprintf(`"logger":%s,`, jsonString(e.Logger.Name())))
+	buf.WriteString(fmt.Sprintf(`"message":%s,`, jsonString(e.Message)))
+
+	data := e.Data
+	if j.staticTopLevel {
+		data = make(Fields, len(e.Data))
+		for k, v := range e.Data {
+			if e.isStatic(k) && !jsonReservedKeys[k] {
+				writeJSONField(&buf, k, v)
+				buf.WriteByte(',')
+			} else {
+				data[k] = v
+			}
+		}
+	}
+
 	buf.WriteString(`"data":{`)
-	dataLen := len(e.Data)
-	for k, v := range e.Data {
-		buf.WriteString(fmt.Sprintf(`"%s":"%v"`, k, v))
+	dataLen := len(data)
+	for k, v := range data {
+		writeJSONField(&buf, k, v)
 		if dataLen > 1 {
 			buf.WriteByte(',')
 		}
@@ -53,12 +75,49 @@ func (j *JSONFormatter) SetTimeFormat(f string) {
 	j.timeFormat = f
 }
 
+// SetStaticFieldsTopLevel writes static fields as keys of the entry object
+// instead of in "data". Static fields named like the entry's own keys, and
+// those overridden by the entry's fields, stay in "data".
+func (j *JSONFormatter) SetStaticFieldsTopLevel(top bool) {
+	j.staticTopLevel = top
+}
+
+// jsonReservedKeys are the keys of a JSONFormatter entry object.
+var jsonReservedKeys = map[string]bool{
+	"timestamp": true,
+	"level":     true,
+	"logger":    true,
+	"message":   true,
+	"data":      true,
+}
+
+// writeJSONField writes a "key":value pair. Errors are written as objects
+// describing the error chain.
+func writeJSONField(buf *bytes.Buffer, k string, v interface{}) {
+	v = resolveValue(v)
+	if err, ok := v.(error); ok {
+		info, _ := json.Marshal(describeError(err))
+		buf.WriteString(fmt.Sprintf(`%s:%s`, jsonString(k), info))
+	} else {
+		buf.WriteString(fmt.Sprintf(`%s:%s`, jsonString(k), jsonString(fmt.Sprintf("%v", v))))
+	}
+}
+
+// jsonString returns s as a quoted and escaped JSON string.
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
 type LineFormatter struct {
+	limiter
+	multiline
 	timeFormat string
 }
 
 func NewLineFormatter() *LineFormatter {
 	return &LineFormatter{
+		multiline:  newMultiline(),
 		timeFormat: time.RFC3339,
 	}
 }
@@ -68,27 +127,30 @@ func (l *LineFormatter) Format(e *Entry) string {
 }
 
 func (l *LineFormatter) FormatByte(e *Entry) []byte {
-	buf := &bytes.Buffer{}
-	fmt.Fprintf(
-		buf,
-		"%s: %s: %s: %s",
+	return l.format(e, l.formatByte)
+}
+
+func (l *LineFormatter) formatByte(e *Entry) []byte {
+	header := fmt.Sprintf(
+		"%s: %s: %s: ",
 		e.Timestamp.Format(l.timeFormat),
 		strings.ToUpper(e.Level.String()),
 		e.Logger.Name(),
-		e.Message,
 	)
+	fields := &bytes.Buffer{}
 	dataLen := len(e.Data)
 	if dataLen > 0 {
-		buf.WriteString(" |")
+		fields.WriteString(" |")
 		for k, v := range e.Data {
-			fmt.Fprintf(buf, ` "%s": "%v"`, k, v)
+			fmt.Fprintf(fields, ` "%s": "%s"`, k, fieldString(v))
 			if dataLen > 1 {
-				buf.WriteByte(',')
+				fields.WriteByte(',')
 			}
 			dataLen--
 		}
 	}
-	buf.WriteByte('\n')
+	buf := &bytes.Buffer{}
+	l.writeLines(buf, header, e.Message, "", fields.String())
 	return buf.Bytes()
 }
 
@@ -97,12 +159,17 @@ func (l *LineFormatter) SetTimeFormat(f string) {
 }
 
 type ColoredLineFormatter struct {
+	limiter
+	multiline
 	timeFormat string
+	theme      Theme
 }
 
 func NewColoredLineFormatter() *ColoredLineFormatter {
 	return &ColoredLineFormatter{
+		multiline:  newMultiline(),
 		timeFormat: time.RFC3339,
+		theme:      DefaultTheme(),
 	}
 }
 
@@ -111,34 +178,162 @@ func (l *ColoredLineFormatter) Format(e *Entry) string {
 }
 
 func (l *ColoredLineFormatter) FormatByte(e *Entry) []byte {
-	buf := &bytes.Buffer{}
-	fmt.Fprintf(
-		buf,
-		"%s%s: %s%s: %s%s: %s%s",
-		ColorGrey,
-		e.Timestamp.Format(l.timeFormat),
-		colors[e.Level],
-		strings.ToUpper(e.Level.String()),
-		ColorGreen,
-		e.Logger.Name(),
-		ColorReset,
-		e.Message,
-	)
+	return l.format(e, l.formatByte)
+}
+
+func (l *ColoredLineFormatter) formatByte(e *Entry) []byte {
+	head := &bytes.Buffer{}
+	writeColored(head, l.theme.Timestamp, e.Timestamp.Format(l.timeFormat))
+	head.WriteString(": ")
+	writeColored(head, l.theme.levelColor(e.Level), strings.ToUpper(e.Level.String()))
+	head.WriteString(": ")
+	writeColored(head, l.theme.Logger, e.Logger.Name())
+	head.WriteString(": ")
+	head.WriteString(string(l.theme.Message))
+	header := head.String()
+	msgEnd := ""
+	if l.theme.Message != "" {
+		msgEnd = string(ColorReset)
+	}
+	fields := &bytes.Buffer{}
 	dataLen := len(e.Data)
 	if dataLen > 0 {
-		buf.WriteString(" |")
+		fields.WriteString(" |")
 		for k, v := range e.Data {
-			fmt.Fprintf(buf, ` "%s": "%v"`, k, v)
+			fields.WriteByte(' ')
+			writeColored(fields, l.theme.FieldKey, fmt.Sprintf(`"%s"`, k))
+			fields.WriteString(": ")
+			writeColored(fields, l.theme.FieldValue, `"`+fieldString(v)+`"`)
 			if dataLen > 1 {
-				buf.WriteByte(',')
+				fields.WriteByte(',')
 			}
 			dataLen--
 		}
 	}
-	buf.WriteByte('\n')
+	buf := &bytes.Buffer{}
+	l.writeLines(buf, header, e.Message, msgEnd, fields.String())
 	return buf.Bytes()
 }
 
 func (l *ColoredLineFormatter) SetTimeFormat(f string) {
 	l.timeFormat = f
 }
+
+// SetTheme sets the colors used by the formatter.
+func (l *ColoredLineFormatter) SetTheme(t Theme) {
+	l.theme = t
+}
+
+// writeColored writes s to buf in color c. If c is empty, s is written
+// uncolored.
+func writeColored(buf *bytes.Buffer, c Color, s string) {
+	if c == "" {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(string(c))
+	buf.WriteString(s)
+	buf.WriteString(string(ColorReset))
+}
+
+// GELFFormatter produces GELF 1.1 JSON messages suitable for Graylog. It's
+// mainly meant to be used with the GELFHandler, the output isn't
+// newline terminated.
+type GELFFormatter struct {
+	limiter
+	host string
+}
+
+func NewGELFFormatter() *GELFFormatter {
+	host, _ := os.Hostname()
+	return &GELFFormatter{
+		host: host,
+	}
+}
+
+func (g *GELFFormatter) Format(e *Entry) string {
+	return string(g.FormatByte(e))
+}
+
+func (g *GELFFormatter) FormatByte(e *Entry) []byte {
+	return g.formatStructured(e, g.formatByte)
+}
+
+func (g *GELFFormatter) formatByte(e *Entry) []byte {
+	short, full := e.Message, ""
+	if i := strings.IndexByte(e.Message, '\n'); i > -1 {
+		short, full = e.Message[:i], e.Message
+	}
+	if short == "" {
+		short = "-" // GELF doesn't allow an empty short_message
+	}
+
+	msg := make(map[string]interface{}, len(e.Data)+7)
+	for k, v := range e.Data {
+		key := gelfFieldName(k)
+		if key == "_id" {
+			continue // Reserved by GELF
+		}
+		msg[key] = gelfFieldValue(v)
+	}
+	msg["version"] = "1.1"
+	msg["host"] = g.host
+	msg["short_message"] = short
+	msg["timestamp"] = float64(e.Timestamp.UnixNano()/int64(time.Millisecond)) / 1000
+	msg["level"] = syslogSeverity(e.Level)
+	msg["_logger"] = e.Logger.Name()
+	if full != "" {
+		msg["full_message"] = full
+	}
+
+	b, _ := json.Marshal(msg)
+	return b
+}
+
+// SetTimeFormat satisfies the interface, NOOP. GELF timestamps are always
+// seconds since the Unix epoch.
+func (g *GELFFormatter) SetTimeFormat(f string) {}
+
+// SetHost changes the host reported in messages. Defaults to os.Hostname().
+func (g *GELFFormatter) SetHost(h string) {
+	g.host = h
+}
+
+// syslogSeverity maps a LogLevel to its syslog severity. Custom levels
+// above Fatal use the notice severity since emergencies are broadcast to
+// every terminal.
+func syslogSeverity(l LogLevel) int {
+	switch {
+	case l <= LogLevelDebug:
+		return 7
+	case l > LogLevelFatal:
+		return 5
+	case l >= LogLevelEmergency:
+		return 0
+	}
+	return int(LogLevelEmergency - l)
+}
+
+// gelfFieldName converts a field key into a valid GELF additional field name.
+func gelfFieldName(k string) string {
+	name := []byte("_" + k)
+	for i := 1; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '_' || c == '.' || c == '-') {
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
+
+// gelfFieldValue returns v if it's a number, otherwise its string form.
+// GELF only allows strings and numbers for additional fields.
+func gelfFieldValue(v interface{}) interface{} {
+	v = resolveValue(v)
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	}
+	return fieldString(v)
+}
@@ -1,6 +1,7 @@
 package verbose
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -30,6 +31,26 @@ func TestJSONFormatter(t *testing.T) {
 	}
 }
 
+func TestJSONFormatterEscaping(t *testing.T) {
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelInfo
+	e.Message = "say \"hi\"\n\tand \\leave"
+	e.Timestamp = time.Now()
+	e.Data = Fields{`quote"key`: `back\slash "value"`}
+
+	var out struct {
+		Message string            `json:"message"`
+		Data    map[string]string `json:"data"`
+	}
+	result := NewJSONFormatter().FormatByte(e)
+	if err := json.Unmarshal(result, &out); err != nil {
+		t.Fatalf("Invalid JSON: %s. Got %s", err.Error(), result)
+	}
+	if out.Message != e.Message || out.Data[`quote"key`] != `back\slash "value"` {
+		t.Errorf("Incorrect values. Got %s", result)
+	}
+}
+
 func TestLineFormatter(t *testing.T) {
 	msg := "My spoon is too big"
 	data := Fields{
@@ -65,11 +86,13 @@ func TestColoredLineFormatter(t *testing.T) {
 	}
 	now := time.Now()
 	expected := fmt.Sprintf(
-		`%s%s: %s%s: %s%s: %s%s | "key1": "value1"%s`,
+		`%s%s%s: %s%s%s: %s%s%s: %s | "key1": "value1"%s`,
 		ColorGrey,
 		now.Format(time.RFC3339),
+		ColorReset,
 		colors[LogLevelInfo],
 		strings.ToUpper(LogLevelInfo.String()),
+		ColorReset,
 		ColorGreen,
 		"logger",
 		ColorReset,
@@ -0,0 +1,212 @@
+package verbose
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// GELFCompression is the compression used for GELF UDP messages
+type GELFCompression int
+
+// Supported GELF compression methods
+const (
+	GELFCompressGzip GELFCompression = iota
+	GELFCompressZlib
+	GELFCompressNone
+)
+
+// Common GELF chunk sizes
+const (
+	GELFChunkSizeWAN = 1420
+	GELFChunkSizeLAN = 8154
+)
+
+const (
+	gelfChunkHeaderLen = 12
+	gelfMaxChunks      = 128
+)
+
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+// GELFHandler sends log messages to a GELF server such as Graylog over
+// UDP or TCP. The formatter should produce GELF messages, the default is a
+// GELFFormatter.
+type GELFHandler struct {
+	levelRange
+	formatterHolder
+	network     string
+	addr        string
+	conn        net.Conn
+	compression GELFCompression
+	chunkSize   int
+	closed      bool
+	m           sync.Mutex
+}
+
+// NewGELFHandler creates a GELFHandler sending to addr. Network must be
+// either "udp" or "tcp". UDP messages are gzip compressed and chunked
+// when larger than GELFChunkSizeWAN. TCP messages are uncompressed and
+// null byte delimited as required by the GELF spec.
+func NewGELFHandler(network, addr string) (*GELFHandler, error) {
+	switch network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("unsupported GELF network %q", network)
+	}
+
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GELFHandler{
+		levelRange:      newLevelRange(),
+		formatterHolder: formatterHolder{formatter: NewGELFFormatter()},
+		network:         network,
+		addr:            addr,
+		conn:            conn,
+		compression:     GELFCompressGzip,
+		chunkSize:       GELFChunkSizeWAN,
+	}, nil
+}
+
+// SetCompression sets the compression used for UDP messages. TCP messages
+// are never compressed.
+func (g *GELFHandler) SetCompression(c GELFCompression) {
+	g.m.Lock()
+	g.compression = c
+	g.m.Unlock()
+}
+
+// SetChunkSize sets the maximum UDP datagram size. Messages larger than
+// this are split into GELF chunks.
+func (g *GELFHandler) SetChunkSize(s int) {
+	if s <= gelfChunkHeaderLen {
+		return
+	}
+	g.m.Lock()
+	g.chunkSize = s
+	g.m.Unlock()
+}
+
+// WriteLog sends the log message to the GELF server.
+func (g *GELFHandler) WriteLog(e *Entry) {
+	msg := g.getFormatter().FormatByte(e)
+
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	var err error
+	if g.closed {
+		err = errors.New("handler is closed")
+	} else if g.isTCP() {
+		err = g.writeTCP(msg)
+	} else {
+		err = g.writeUDP(msg)
+	}
+	if err != nil {
+		fmt.Printf("Error writing GELF message: %v\n", err)
+	}
+}
+
+func (g *GELFHandler) isTCP() bool {
+	return g.network[:3] == "tcp"
+}
+
+func (g *GELFHandler) writeTCP(msg []byte) error {
+	msg = append(msg, 0)
+	if g.conn != nil {
+		if _, err := g.conn.Write(msg); err == nil {
+			return nil
+		}
+		g.conn.Close()
+	}
+
+	// Reconnect once, the server may have closed an idle connection
+	conn, err := net.Dial(g.network, g.addr)
+	if err != nil {
+		g.conn = nil
+		return err
+	}
+	g.conn = conn
+	_, err = g.conn.Write(msg)
+	return err
+}
+
+func (g *GELFHandler) writeUDP(msg []byte) error {
+	msg, err := g.compress(msg)
+	if err != nil {
+		return err
+	}
+
+	if len(msg) <= g.chunkSize {
+		_, err := g.conn.Write(msg)
+		return err
+	}
+
+	dataLen := g.chunkSize - gelfChunkHeaderLen
+	count := (len(msg) + dataLen - 1) / dataLen
+	if count > gelfMaxChunks {
+		return fmt.Errorf("message too large, needs %d chunks, max %d", count, gelfMaxChunks)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 0, g.chunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * dataLen
+		if end > len(msg) {
+			end = len(msg)
+		}
+
+		chunk = append(chunk[:0], gelfChunkMagic...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, msg[i*dataLen:end]...)
+		if _, err := g.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *GELFHandler) compress(msg []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	switch g.compression {
+	case GELFCompressGzip:
+		w := gzip.NewWriter(buf)
+		w.Write(msg)
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	case GELFCompressZlib:
+		w := zlib.NewWriter(buf)
+		w.Write(msg)
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return msg, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// Close closes the connection to the GELF server.
+func (g *GELFHandler) Close() {
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.closed = true
+	if g.conn != nil {
+		g.conn.Close()
+		g.conn = nil
+	}
+}
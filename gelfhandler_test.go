@@ -0,0 +1,184 @@
+package verbose
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestGELFEntry(msg string) *Entry {
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelError
+	e.Message = msg
+	e.Timestamp = time.Unix(1500000000, 0)
+	e.Data = Fields{"key1": "value1", "count": 42}
+	return e
+}
+
+func checkGELFMessage(t *testing.T, data []byte, msg string) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Invalid GELF JSON: %s", err.Error())
+	}
+	if m["short_message"] != msg {
+		t.Errorf("Incorrect short_message. Expected %s, got %v", msg, m["short_message"])
+	}
+	if m["level"] != float64(3) {
+		t.Errorf("Incorrect level. Expected 3, got %v", m["level"])
+	}
+	if m["_key1"] != "value1" {
+		t.Errorf("Incorrect field. Expected value1, got %v", m["_key1"])
+	}
+	if m["_count"] != float64(42) {
+		t.Errorf("Incorrect field. Expected 42, got %v", m["_count"])
+	}
+}
+
+func TestGELFHandlerUDP(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Error creating listener: %s", err.Error())
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	gh, err := NewGELFHandler("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Error making GELF handler: %s", err.Error())
+	}
+	defer gh.Close()
+
+	gh.WriteLog(newTestGELFEntry("Everything is on fire"))
+
+	buf := make([]byte, 65536)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Error reading datagram: %s", err.Error())
+	}
+	r, err := gzip.NewReader(bytes.NewReader(buf[:n]))
+	if err != nil {
+		t.Fatalf("Message not gzip compressed: %s", err.Error())
+	}
+	data, _ := ioutil.ReadAll(r)
+	checkGELFMessage(t, data, "Everything is on fire")
+}
+
+func TestGELFHandlerUDPChunked(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Error creating listener: %s", err.Error())
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	gh, err := NewGELFHandler("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Error making GELF handler: %s", err.Error())
+	}
+	defer gh.Close()
+	gh.SetCompression(GELFCompressZlib)
+	gh.SetChunkSize(50)
+
+	gh.WriteLog(newTestGELFEntry("Everything is on fire"))
+
+	var id []byte
+	var data []byte
+	buf := make([]byte, 65536)
+	for seq, count := 0, 1; seq < count; seq++ {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("Error reading chunk: %s", err.Error())
+		}
+		if n > 50 {
+			t.Errorf("Chunk too large. Expected at most 50, got %d", n)
+		}
+		if !bytes.Equal(buf[:2], gelfChunkMagic) {
+			t.Fatal("Chunk missing magic bytes")
+		}
+		if id == nil {
+			id = append(id, buf[2:10]...)
+		} else if !bytes.Equal(id, buf[2:10]) {
+			t.Error("Chunk message IDs don't match")
+		}
+		if int(buf[10]) != seq {
+			t.Errorf("Incorrect sequence number. Expected %d, got %d", seq, buf[10])
+		}
+		count = int(buf[11])
+		data = append(data, buf[12:n]...)
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Message not zlib compressed: %s", err.Error())
+	}
+	data, _ = ioutil.ReadAll(r)
+	checkGELFMessage(t, data, "Everything is on fire")
+}
+
+func TestGELFHandlerTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error creating listener: %s", err.Error())
+	}
+	defer ln.Close()
+
+	gh, err := NewGELFHandler("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("Error making GELF handler: %s", err.Error())
+	}
+	defer gh.Close()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Error accepting connection: %s", err.Error())
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	gh.WriteLog(newTestGELFEntry("First"))
+	gh.WriteLog(newTestGELFEntry("Second\nwith more detail"))
+
+	r := bufio.NewReader(conn)
+	for _, msg := range []string{"First", "Second"} {
+		data, err := r.ReadBytes(0)
+		if err != nil {
+			t.Fatalf("Error reading message: %s", err.Error())
+		}
+		checkGELFMessage(t, data[:len(data)-1], msg)
+	}
+}
+
+func TestGELFFormatter(t *testing.T) {
+	formatter := NewGELFFormatter()
+	formatter.SetHost("example")
+
+	e := newTestGELFEntry("Line one\nLine two")
+	e.Data["bad key!"] = true
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(formatter.FormatByte(e), &m); err != nil {
+		t.Fatalf("Invalid GELF JSON: %s", err.Error())
+	}
+
+	expected := map[string]interface{}{
+		"version":       "1.1",
+		"host":          "example",
+		"short_message": "Line one",
+		"full_message":  "Line one\nLine two",
+		"timestamp":     float64(1500000000),
+		"level":         float64(3),
+		"_logger":       "logger",
+		"_bad_key_":     "true",
+	}
+	for k, v := range expected {
+		if m[k] != v {
+			t.Errorf("Incorrect %s. Expected %v, got %v", k, v, m[k])
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package verbose
 
-import "log"
+import (
+	"log"
+	"sync"
+)
 
 // A Handler is an object that can be used by the Logger to log a message
 type Handler interface {
@@ -28,6 +31,116 @@ type Handler interface {
 	SetMaxLevel(LogLevel)
 }
 
+// LevelReporter is implemented by handlers that can report the range of
+// levels they handle. All the included handlers implement it.
+type LevelReporter interface {
+	MinLevel() LogLevel
+	MaxLevel() LogLevel
+}
+
+// LevelRangeSetter is implemented by handlers that can change their minimum
+// and maximum levels in a single step, so no entry sees a partial change.
+// All the included handlers implement it.
+type LevelRangeSetter interface {
+	SetLevelRange(min, max LogLevel)
+}
+
+// levelRange holds the minimum and maximum levels of a handler. It's
+// embedded in the included handlers to provide the level methods of Handler,
+// LevelReporter and LevelRangeSetter. It's safe to change the levels while
+// logging.
+type levelRange struct {
+	min LogLevel
+	max LogLevel
+	lm  sync.RWMutex
+}
+
+func newLevelRange() levelRange {
+	return levelRange{
+		min: LogLevelDebug,
+		max: LogLevelFatal,
+	}
+}
+
+// SetLevel will set both the minimum and maximum log levels to l. This makes
+// the handler only respond to the single level l.
+func (r *levelRange) SetLevel(l LogLevel) {
+	r.lm.Lock()
+	r.min = l
+	r.max = l
+	r.lm.Unlock()
+}
+
+// SetMinLevel will set the minimum log level the handler will handle.
+func (r *levelRange) SetMinLevel(l LogLevel) {
+	r.lm.Lock()
+	if l <= r.max {
+		r.min = l
+	}
+	r.lm.Unlock()
+}
+
+// SetMaxLevel will set the maximum log level the handler will handle.
+func (r *levelRange) SetMaxLevel(l LogLevel) {
+	r.lm.Lock()
+	if l >= r.min {
+		r.max = l
+	}
+	r.lm.Unlock()
+}
+
+// SetLevelRange sets both the minimum and maximum log levels. Nothing is
+// changed if min is above max.
+func (r *levelRange) SetLevelRange(min, max LogLevel) {
+	r.lm.Lock()
+	if min <= max {
+		r.min = min
+		r.max = max
+	}
+	r.lm.Unlock()
+}
+
+// MinLevel returns the minimum log level the handler will handle.
+func (r *levelRange) MinLevel() LogLevel {
+	r.lm.RLock()
+	defer r.lm.RUnlock()
+	return r.min
+}
+
+// MaxLevel returns the maximum log level the handler will handle.
+func (r *levelRange) MaxLevel() LogLevel {
+	r.lm.RLock()
+	defer r.lm.RUnlock()
+	return r.max
+}
+
+// Handles returns whether the handler handles log level l.
+func (r *levelRange) Handles(l LogLevel) bool {
+	r.lm.RLock()
+	defer r.lm.RUnlock()
+	return (r.min <= l && l <= r.max)
+}
+
+// formatterHolder holds the formatter of a handler. It's safe to change the
+// formatter while logging.
+type formatterHolder struct {
+	formatter Formatter
+	fm        sync.RWMutex
+}
+
+// SetFormatter gives the handler a formatter for log messages.
+func (f *formatterHolder) SetFormatter(fo Formatter) {
+	f.fm.Lock()
+	f.formatter = fo
+	f.fm.Unlock()
+}
+
+func (f *formatterHolder) getFormatter() Formatter {
+	f.fm.RLock()
+	defer f.fm.RUnlock()
+	return f.formatter
+}
+
 // Won't compile if StdLogger can't be realized by a log.Logger
 var (
 	_ StdLogger = &log.Logger{}
@@ -0,0 +1,184 @@
+//go:build linux
+// +build linux
+
+package verbose
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// JournaldSocket is the default path of the journal's native socket
+const JournaldSocket = "/run/systemd/journal/socket"
+
+func init() {
+	RegisterHandlerType("journald", func(c HandlerConfig) (Handler, error) {
+		return NewJournaldHandler(c.Path)
+	})
+}
+
+// JournaldHandler writes log messages to the systemd journal using its
+// native protocol. The message is sent as MESSAGE, the level as PRIORITY
+// and the logger name as SYSLOG_IDENTIFIER. Each field is sent as an
+// uppercase journal field. Fields that would clash with the ones written by
+// the handler are prefixed with FIELD_, e.g. FIELD_MESSAGE.
+//
+// By default no formatter is used and MESSAGE is the plain log message since
+// the journal stores the timestamp, level and fields itself. If a formatter
+// is set, it's used to build MESSAGE.
+type JournaldHandler struct {
+	levelRange
+	formatterHolder
+	addr *net.UnixAddr
+	conn *net.UnixConn
+	m    sync.Mutex
+}
+
+// NewJournaldHandler creates a JournaldHandler writing to the journal socket
+// at path. If path is "", JournaldSocket is used.
+func NewJournaldHandler(path string) (*JournaldHandler, error) {
+	if path == "" {
+		path = JournaldSocket
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournaldHandler{
+		levelRange: newLevelRange(),
+		addr:       &net.UnixAddr{Name: path, Net: "unixgram"},
+		conn:       conn,
+	}, nil
+}
+
+// WriteLog sends the log message to the journal.
+func (j *JournaldHandler) WriteLog(e *Entry) {
+	msg := e.Message
+	if f := j.getFormatter(); f != nil {
+		msg = strings.TrimSuffix(f.Format(e), "\n")
+	}
+
+	buf := &bytes.Buffer{}
+	writeJournalField(buf, "MESSAGE", msg)
+	writeJournalField(buf, "PRIORITY", strconv.Itoa(syslogSeverity(e.Level)))
+	writeJournalField(buf, "SYSLOG_IDENTIFIER", e.Logger.Name())
+	for k, v := range e.Data {
+		writeJournalField(buf, journalFieldName(k), fieldString(v))
+	}
+
+	j.m.Lock()
+	defer j.m.Unlock()
+
+	if j.conn == nil {
+		fmt.Println("Error writing to journal: handler is closed")
+		return
+	}
+
+	_, _, err := j.conn.WriteMsgUnix(buf.Bytes(), nil, j.addr)
+	if isMsgTooLarge(err) {
+		err = j.writeFile(buf.Bytes())
+	}
+	if err != nil {
+		fmt.Printf("Error writing to journal: %v\n", err)
+	}
+}
+
+// writeFile passes an oversized entry to journald as a file descriptor
+// of an unlinked temporary file.
+func (j *JournaldHandler) writeFile(data []byte) error {
+	dir := "/dev/shm"
+	if _, err := os.Stat(dir); err != nil {
+		dir = ""
+	}
+
+	file, err := ioutil.TempFile(dir, "journal.")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := os.Remove(file.Name()); err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+
+	rights := syscall.UnixRights(int(file.Fd()))
+	_, _, err = j.conn.WriteMsgUnix(nil, rights, j.addr)
+	return err
+}
+
+// Close closes the socket used to write to the journal.
+func (j *JournaldHandler) Close() {
+	j.m.Lock()
+	defer j.m.Unlock()
+	if j.conn != nil {
+		j.conn.Close()
+		j.conn = nil
+	}
+}
+
+func isMsgTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// writeJournalField writes a single field in the journal's native format.
+// Values containing newlines use the binary length prefixed form.
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.ContainsRune(value, '\n') {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// reservedJournalFields are the journal fields written by JournaldHandler
+// itself.
+var reservedJournalFields = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+}
+
+// journalFieldName converts a field key into a valid journal field name.
+// Names may only contain uppercase letters, digits and underscores and can't
+// start with an underscore or digit. Reserved names are prefixed.
+func journalFieldName(k string) string {
+	name := []byte(strings.ToUpper(k))
+	for i, c := range name {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			name[i] = '_'
+		}
+	}
+
+	n := strings.TrimLeft(string(name), "_")
+	if n == "" || n[0] >= '0' && n[0] <= '9' || reservedJournalFields[n] {
+		n = "FIELD_" + n
+	}
+	if len(n) > 64 {
+		n = n[:64]
+	}
+	return n
+}
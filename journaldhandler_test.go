@@ -0,0 +1,165 @@
+//go:build linux
+// +build linux
+
+package verbose
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func newTestJournal(t *testing.T) (*net.UnixConn, string, func()) {
+	dir, err := ioutil.TempDir("", "verbose-journal")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err.Error())
+	}
+	path := filepath.Join(dir, "socket")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Error creating listener: %s", err.Error())
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn, path, func() {
+		conn.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// parseJournalFields decodes a message in the journal native format.
+func parseJournalFields(t *testing.T, data []byte) map[string]string {
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		nl := bytes.IndexByte(data, '\n')
+		if nl == -1 {
+			t.Fatal("Unterminated journal field")
+		}
+		line := string(data[:nl])
+		data = data[nl+1:]
+
+		if eq := strings.IndexByte(line, '='); eq > -1 {
+			fields[line[:eq]] = line[eq+1:]
+			continue
+		}
+
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[line] = string(data[8 : 8+size])
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournaldHandlerWriteLog(t *testing.T) {
+	conn, path, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	jh, err := NewJournaldHandler(path)
+	if err != nil {
+		t.Fatalf("Error making journald handler: %s", err.Error())
+	}
+	defer jh.Close()
+
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelWarning
+	e.Message = "What? No coffee!?"
+	e.Data = Fields{"request id": 42, "trace": "line 1\nline 2", "message": "spoofed"}
+	jh.WriteLog(e)
+
+	buf := make([]byte, 65536)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Error reading datagram: %s", err.Error())
+	}
+
+	fields := parseJournalFields(t, buf[:n])
+	expected := map[string]string{
+		"MESSAGE":           "What? No coffee!?",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "logger",
+		"REQUEST_ID":        "42",
+		"TRACE":             "line 1\nline 2",
+		"FIELD_MESSAGE":     "spoofed",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("Incorrect %s field. Expected %q, got %q", k, v, fields[k])
+		}
+	}
+}
+
+func TestJournaldHandlerOversized(t *testing.T) {
+	conn, path, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	jh, err := NewJournaldHandler(path)
+	if err != nil {
+		t.Fatalf("Error making journald handler: %s", err.Error())
+	}
+	defer jh.Close()
+
+	msg := strings.Repeat("a", 4*1024*1024)
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelInfo
+	e.Message = msg
+	jh.WriteLog(e)
+
+	buf := make([]byte, 1024)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatalf("Error reading datagram: %s", err.Error())
+	}
+	if n != 0 {
+		t.Errorf("Expected empty datagram, got %d bytes", n)
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("Expected a single control message: %v", err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("Expected a single file descriptor: %v", err)
+	}
+
+	file := os.NewFile(uintptr(fds[0]), "journal")
+	defer file.Close()
+	file.Seek(0, 0)
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		t.Fatalf("Error reading passed file: %s", err.Error())
+	}
+
+	fields := parseJournalFields(t, data)
+	if fields["MESSAGE"] != msg {
+		t.Errorf("Incorrect MESSAGE field. Expected %d bytes, got %d", len(msg), len(fields["MESSAGE"]))
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	names := map[string]string{
+		"user":              "USER",
+		"user-id":           "USER_ID",
+		"_private":          "PRIVATE",
+		"2fa":               "FIELD_2FA",
+		"__":                "FIELD_",
+		"CamelCase":         "CAMELCASE",
+		"message":           "FIELD_MESSAGE",
+		"Priority":          "FIELD_PRIORITY",
+		"syslog-identifier": "FIELD_SYSLOG_IDENTIFIER",
+	}
+	for in, expected := range names {
+		if out := journalFieldName(in); out != expected {
+			t.Errorf("Incorrect field name for %q. Expected %s, got %s", in, expected, out)
+		}
+	}
+}
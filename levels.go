@@ -0,0 +1,93 @@
+package verbose
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type customLevel struct {
+	name  string
+	color Color
+}
+
+var (
+	customLevels      = make(map[LogLevel]customLevel)
+	customLevelNames  = make(map[string]LogLevel)
+	customLevelsMutex = sync.RWMutex{}
+)
+
+// RegisterLevel adds a custom log level. The level's number decides where
+// it falls between the built-in levels, e.g. LogLevel(-1) is below Debug and
+// LogLevel(100) is above Fatal. The name is used by formatters and ParseLevel,
+// the color by the ColoredLineFormatter. Log at a custom level with
+// Logger.Log or Entry.Log.
+//
+// The included handlers default to handling Debug through Fatal. Their
+// levels need to be changed to handle custom levels outside that range. The
+// journald and GELF handlers send custom levels below Debug with the debug
+// severity and those above Fatal with the notice severity.
+func RegisterLevel(level LogLevel, name string, color Color) error {
+	if name == "" || strings.IndexAny(name, " \t\r\n=,") > -1 {
+		return fmt.Errorf("invalid level name %q", name)
+	}
+
+	customLevelsMutex.Lock()
+	defer customLevelsMutex.Unlock()
+
+	if s, ok := levelString[level]; ok {
+		return fmt.Errorf("level %d is already used by %s", int(level), s)
+	}
+	if c, ok := customLevels[level]; ok {
+		return fmt.Errorf("level %d is already used by %s", int(level), c.name)
+	}
+
+	lower := strings.ToLower(name)
+	if _, ok := levelNames[lower]; ok {
+		return fmt.Errorf("level name %q is already used", name)
+	}
+	if _, ok := customLevelNames[lower]; ok {
+		return fmt.Errorf("level name %q is already used", name)
+	}
+
+	customLevels[level] = customLevel{name: name, color: color}
+	customLevelNames[lower] = level
+	return nil
+}
+
+// unregisterLevel removes custom level l. It's used by tests to leave the
+// registry as they found it.
+func unregisterLevel(l LogLevel) {
+	customLevelsMutex.Lock()
+	defer customLevelsMutex.Unlock()
+	if c, ok := customLevels[l]; ok {
+		delete(customLevelNames, strings.ToLower(c.name))
+		delete(customLevels, l)
+	}
+}
+
+// customLevelName returns the name of custom level l or "" if it isn't
+// registered.
+func customLevelName(l LogLevel) string {
+	customLevelsMutex.RLock()
+	defer customLevelsMutex.RUnlock()
+	return customLevels[l].name
+}
+
+// lookupCustomLevel returns the custom level with lowercase name.
+func lookupCustomLevel(name string) (LogLevel, bool) {
+	customLevelsMutex.RLock()
+	defer customLevelsMutex.RUnlock()
+	l, ok := customLevelNames[name]
+	return l, ok
+}
+
+// levelColor returns the terminal color of level l.
+func levelColor(l LogLevel) Color {
+	if c, ok := colors[l]; ok {
+		return c
+	}
+	customLevelsMutex.RLock()
+	defer customLevelsMutex.RUnlock()
+	return customLevels[l].color
+}
@@ -0,0 +1,98 @@
+package verbose
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterLevel(t *testing.T) {
+	audit := LogLevel(100)
+	if err := RegisterLevel(audit, "Audit", ColorYellow); err != nil {
+		t.Fatalf("Error registering level: %s", err.Error())
+	}
+	t.Cleanup(func() { unregisterLevel(audit) })
+
+	if audit.String() != "Audit" {
+		t.Errorf("Incorrect level name. Expected Audit, got %s", audit.String())
+	}
+	for _, name := range []string{"audit", "AUDIT", "100"} {
+		l, err := ParseLevel(name)
+		if err != nil || l != audit {
+			t.Errorf("Incorrect level for %q. Expected %d, got %d (%v)", name, audit, l, err)
+		}
+	}
+
+	tests := []struct {
+		level LogLevel
+		name  string
+	}{
+		{audit, "Other"},
+		{LogLevelDebug, "Other"},
+		{101, "audit"},
+		{101, "Warning"},
+		{101, "warn"},
+		{101, ""},
+		{101, "Two words"},
+	}
+	for _, test := range tests {
+		if err := RegisterLevel(test.level, test.name, ColorRed); err == nil {
+			t.Errorf("Expected error registering %d %q", test.level, test.name)
+		}
+	}
+}
+
+func TestLogCustomLevel(t *testing.T) {
+	clearLoggers()
+	security := LogLevel(-5)
+	if err := RegisterLevel(security, "Security", ColorMagenta); err != nil {
+		t.Fatalf("Error registering level: %s", err.Error())
+	}
+	t.Cleanup(func() { unregisterLevel(security) })
+
+	logger := New("logger")
+	logger.AddHandler("h", newTestHandler(t, security, "logger", "Intruder alert"))
+	logger.Log(security, "Intruder alert")
+	logger.WithField("user", "root").Log(security, "Intruder alert")
+
+	now := time.Now()
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = security
+	e.Message = "Intruder alert"
+	e.Timestamp = now
+
+	expected := fmt.Sprintf("%s: SECURITY: logger: Intruder alert\n", now.Format(time.RFC3339))
+	if result := NewLineFormatter().Format(e); result != expected {
+		t.Errorf("Incorrectly formatted message. Expected `%s`, got `%s`", expected, result)
+	}
+	if result := NewColoredLineFormatter().Format(e); !strings.Contains(result, string(ColorMagenta)+"SECURITY") {
+		t.Errorf("Custom level color not used. Got `%s`", result)
+	}
+
+	sh := NewStdoutHandler(false)
+	if sh.Handles(security) {
+		t.Error("Default handler shouldn't handle level below Debug")
+	}
+	sh.SetMinLevel(security)
+	if !sh.Handles(security) {
+		t.Error("Handler should handle custom level after changing its range")
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := map[LogLevel]int{
+		LogLevel(-5):      7,
+		LogLevelTrace:     7,
+		LogLevelDebug:     7,
+		LogLevelError:     3,
+		LogLevelEmergency: 0,
+		LogLevelFatal:     0,
+		LogLevel(100):     5,
+	}
+	for l, expected := range tests {
+		if s := syslogSeverity(l); s != expected {
+			t.Errorf("Incorrect severity for level %d. Expected %d, got %d", l, expected, s)
+		}
+	}
+}
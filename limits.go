@@ -0,0 +1,233 @@
+package verbose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TruncationMarker is appended to truncated messages and field values.
+const TruncationMarker = "…(truncated)"
+
+// TruncatedFieldsKey is the field holding the number of fields dropped from
+// an entry because of limits.
+const TruncatedFieldsKey = "truncated_fields"
+
+// Limits restricts the size of formatted entries. Lengths are in bytes and a
+// zero value means no limit. Truncated messages and values end with
+// TruncationMarker and dropped fields are counted in the TruncatedFieldsKey
+// field. Errors keep their type, causes and stack trace, only their messages
+// are truncated. If an entry is still larger than MaxEntrySize, its fields
+// are dropped and then its message is shortened to fit. As a last resort,
+// line formats are cut at MaxEntrySize while JSON formats are written with
+// an empty message so they stay valid, even if that's still too large.
+type Limits struct {
+	MaxMessageLength    int `json:"max_message_length,omitempty"`
+	MaxFieldValueLength int `json:"max_field_value_length,omitempty"`
+	MaxFields           int `json:"max_fields,omitempty"`
+	MaxEntrySize        int `json:"max_entry_size,omitempty"`
+}
+
+// A Limiter is a formatter that enforces Limits. All the included
+// formatters are Limiters.
+type Limiter interface {
+	SetLimits(Limits)
+	Truncated() uint64
+}
+
+// limiter holds the limits of a formatter. It's embedded in the included
+// formatters to provide the Limiter methods.
+type limiter struct {
+	limits    Limits
+	truncated uint64
+	m         sync.Mutex
+}
+
+// SetLimits sets the size limits of formatted entries.
+func (l *limiter) SetLimits(limits Limits) {
+	l.m.Lock()
+	l.limits = limits
+	l.m.Unlock()
+}
+
+// Truncated returns the number of entries that were truncated.
+func (l *limiter) Truncated() uint64 {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.truncated
+}
+
+// format formats e with render while enforcing the limits. Output that's
+// still too large after shrinking the entry is cut.
+func (l *limiter) format(e *Entry, render func(*Entry) []byte) []byte {
+	return l.formatLimited(e, render, true)
+}
+
+// formatStructured is like format for formats, such as JSON, that would be
+// invalid if cut.
+func (l *limiter) formatStructured(e *Entry, render func(*Entry) []byte) []byte {
+	return l.formatLimited(e, render, false)
+}
+
+func (l *limiter) formatLimited(e *Entry, render func(*Entry) []byte, cut bool) []byte {
+	l.m.Lock()
+	limits := l.limits
+	l.m.Unlock()
+	if limits == (Limits{}) {
+		return render(e)
+	}
+
+	e, truncated := limits.apply(e)
+	out := render(e)
+	if limits.MaxEntrySize > 0 && len(out) > limits.MaxEntrySize {
+		truncated = true
+		out = limits.shrink(e, out, render, cut)
+	}
+
+	if truncated {
+		l.m.Lock()
+		l.truncated++
+		l.m.Unlock()
+	}
+	return out
+}
+
+// apply returns e with the message, field and value limits applied. If
+// nothing needs to be changed, e itself is returned. LogValuers are resolved
+// once here when values are limited so render doesn't resolve them again.
+func (limits Limits) apply(e *Entry) (*Entry, bool) {
+	msgTooLong := limits.MaxMessageLength > 0 && len(e.Message) > limits.MaxMessageLength
+	tooManyFields := limits.MaxFields > 0 && len(e.Data) > limits.MaxFields
+
+	values := make(map[string]interface{})
+	longValues := false
+	if limits.MaxFieldValueLength > 0 {
+		for k, v := range e.Data {
+			_, isValuer := v.(LogValuer)
+			if isValuer {
+				v = resolveValue(v)
+			}
+			if t, ok := limits.truncateValue(v); ok {
+				values[k] = t
+				longValues = true
+			} else if isValuer {
+				values[k] = v
+			}
+		}
+	}
+
+	if !msgTooLong && !tooManyFields && len(values) == 0 {
+		return e, false
+	}
+
+	c := e.Clone()
+	if msgTooLong {
+		c.Message = truncate(c.Message, limits.MaxMessageLength)
+	}
+	for k, v := range values {
+		c.Data[k] = v
+	}
+	if tooManyFields {
+		keys := make([]string, 0, len(c.Data))
+		for k := range c.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys[limits.MaxFields:] {
+			delete(c.Data, k)
+		}
+		c.Data[TruncatedFieldsKey] = len(keys) - limits.MaxFields
+	}
+	return c, msgTooLong || tooManyFields || longValues
+}
+
+// truncateValue returns resolved field value v truncated to
+// MaxFieldValueLength and whether it was too long. Errors are replaced by a
+// truncatedError.
+func (limits Limits) truncateValue(v interface{}) (interface{}, bool) {
+	if err, ok := v.(error); ok {
+		if len(err.Error()) <= limits.MaxFieldValueLength {
+			return v, false
+		}
+		return &truncatedError{info: truncateErrorInfo(describeError(err), limits.MaxFieldValueLength)}, true
+	}
+
+	s := fmt.Sprintf("%v", v)
+	if len(s) <= limits.MaxFieldValueLength {
+		return v, false
+	}
+	return truncate(s, limits.MaxFieldValueLength), true
+}
+
+// truncatedError is an error whose messages were truncated. It's rendered
+// with the description of the original error.
+type truncatedError struct {
+	info errorInfo
+}
+
+func (t *truncatedError) Error() string {
+	return t.info.Message
+}
+
+func truncateErrorInfo(info errorInfo, n int) errorInfo {
+	info.Message = truncate(info.Message, n)
+	causes := make([]errorInfo, len(info.Causes))
+	for i, cause := range info.Causes {
+		causes[i] = truncateErrorInfo(cause, n)
+	}
+	info.Causes = causes
+	return info
+}
+
+// shrink renders e again to fit in MaxEntrySize, first without fields then
+// with a shorter message. If the entry still doesn't fit, the output is cut
+// if cut is true. Otherwise the entry is rendered with an empty message.
+func (limits Limits) shrink(e *Entry, out []byte, render func(*Entry) []byte, cut bool) []byte {
+	c := e.Clone()
+	if len(e.Data) > 0 {
+		dropped := len(e.Data)
+		if n, ok := e.Data[TruncatedFieldsKey].(int); ok {
+			dropped += n - 1
+		}
+		c.Data = Fields{TruncatedFieldsKey: dropped}
+		out = render(c)
+		if len(out) <= limits.MaxEntrySize {
+			return out
+		}
+	}
+
+	over := len(out) - limits.MaxEntrySize + len(TruncationMarker)
+	if over < len(c.Message) {
+		c.Message = truncate(strings.TrimSuffix(c.Message, TruncationMarker), len(c.Message)-over)
+		out = render(c)
+		if len(out) <= limits.MaxEntrySize {
+			return out
+		}
+	}
+
+	if !cut {
+		c.Message = ""
+		return render(c)
+	}
+
+	newline := out[len(out)-1] == '\n'
+	out = out[:limits.MaxEntrySize]
+	if newline {
+		out[len(out)-1] = '\n'
+	}
+	return out
+}
+
+// truncate cuts s to at most n bytes without splitting a character and adds
+// TruncationMarker.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + TruncationMarker
+}
@@ -0,0 +1,183 @@
+package verbose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newLimitsTestEntry() *Entry {
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelInfo
+	e.Message = "Request body received"
+	e.Timestamp = time.Now()
+	e.Data = Fields{
+		"a_body": strings.Repeat("x", 100),
+		"b_id":   42,
+		"c_path": "/upload",
+	}
+	return e
+}
+
+func TestLimitsFields(t *testing.T) {
+	formatter := NewLineFormatter()
+	formatter.SetLimits(Limits{
+		MaxMessageLength:    7,
+		MaxFieldValueLength: 10,
+		MaxFields:           2,
+	})
+
+	e := newLimitsTestEntry()
+	result := formatter.Format(e)
+	for _, s := range []string{
+		": Request" + TruncationMarker + " |",
+		`"a_body": "xxxxxxxxxx` + TruncationMarker + `"`,
+		`"b_id": "42"`,
+		`"truncated_fields": "1"`,
+	} {
+		if !strings.Contains(result, s) {
+			t.Errorf("Output doesn't contain `%s`. Got `%s`", s, result)
+		}
+	}
+	if strings.Contains(result, "c_path") {
+		t.Errorf("Field not dropped. Got `%s`", result)
+	}
+	if len(e.Data) != 3 || e.Message != "Request body received" {
+		t.Error("Original entry was changed")
+	}
+	if n := formatter.Truncated(); n != 1 {
+		t.Errorf("Incorrect truncated count. Expected 1, got %d", n)
+	}
+
+	e.Data = Fields{"id": 1}
+	e.Message = "Short"
+	formatter.Format(e)
+	if n := formatter.Truncated(); n != 1 {
+		t.Errorf("Entry within limits counted as truncated. Got %d", n)
+	}
+}
+
+func TestLimitsEntrySize(t *testing.T) {
+	formatter := NewJSONFormatter()
+	formatter.SetLimits(Limits{MaxEntrySize: 200})
+
+	e := newLimitsTestEntry()
+	result := formatter.FormatByte(e)
+	if len(result) > 200 {
+		t.Errorf("Entry too large. Expected at most 200 bytes, got %d", len(result))
+	}
+	var out struct {
+		Message string            `json:"message"`
+		Data    map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(result, &out); err != nil {
+		t.Fatalf("Invalid JSON: %s", err.Error())
+	}
+	if out.Message != e.Message || len(out.Data) != 1 || out.Data[TruncatedFieldsKey] != "3" {
+		t.Errorf("Fields not dropped. Got %s", result)
+	}
+
+	e.Data = Fields{}
+	e.Message = strings.Repeat("y", 500)
+	result = formatter.FormatByte(e)
+	if len(result) > 200 || result[len(result)-1] != '\n' {
+		t.Errorf("Entry too large. Expected at most 200 bytes, got %d", len(result))
+	}
+	if err := json.Unmarshal(result, &out); err != nil {
+		t.Fatalf("Invalid JSON: %s", err.Error())
+	}
+	if !strings.HasSuffix(out.Message, TruncationMarker) {
+		t.Errorf("Message not truncated. Got %s", result)
+	}
+	if n := formatter.Truncated(); n != 2 {
+		t.Errorf("Incorrect truncated count. Expected 2, got %d", n)
+	}
+}
+
+func TestLimitsStructuredStaysValid(t *testing.T) {
+	e := newLimitsTestEntry()
+	e.Message = strings.Repeat("y", 500)
+
+	for _, f := range []interface {
+		Formatter
+		Limiter
+	}{NewJSONFormatter(), NewGELFFormatter()} {
+		f.SetLimits(Limits{MaxEntrySize: 20})
+		result := f.FormatByte(e)
+		var out map[string]interface{}
+		if err := json.Unmarshal(result, &out); err != nil {
+			t.Fatalf("Invalid JSON from %T: %s. Got %s", f, err.Error(), result)
+		}
+		if strings.Contains(string(result), "yyy") || strings.Contains(string(result), "xxx") {
+			t.Errorf("Message and fields not removed by %T. Got %s", f, result)
+		}
+	}
+
+	line := NewLineFormatter()
+	line.SetLimits(Limits{MaxEntrySize: 20})
+	if result := line.FormatByte(e); len(result) != 20 {
+		t.Errorf("Line output not cut. Expected 20 bytes, got %d", len(result))
+	}
+}
+
+func TestLimitsError(t *testing.T) {
+	err := fmt.Errorf("upload failed: %w", errors.New(strings.Repeat("z", 50)))
+	e := newLimitsTestEntry()
+	e.Data = Fields{ErrorKey: err}
+
+	formatter := NewJSONFormatter()
+	formatter.SetLimits(Limits{MaxFieldValueLength: 10})
+	var out struct {
+		Data map[string]errorInfo `json:"data"`
+	}
+	if err := json.Unmarshal(formatter.FormatByte(e), &out); err != nil {
+		t.Fatalf("Invalid JSON: %s", err.Error())
+	}
+	info := out.Data[ErrorKey]
+	if info.Message != "upload fai"+TruncationMarker || info.Type != "*fmt.wrapError" {
+		t.Errorf("Incorrect error description. Got %+v", info)
+	}
+	if len(info.Causes) != 1 || info.Causes[0].Message != "zzzzzzzzzz"+TruncationMarker || info.Causes[0].Type != "*errors.errorString" {
+		t.Errorf("Incorrect error causes. Got %+v", info.Causes)
+	}
+
+	line := NewLineFormatter()
+	line.SetLimits(Limits{MaxFieldValueLength: 10})
+	expected := `"error": "upload fai` + TruncationMarker + ` [*fmt.wrapError <- *errors.errorString]"`
+	if result := line.Format(e); !strings.Contains(result, expected) {
+		t.Errorf("Output doesn't contain `%s`. Got `%s`", expected, result)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s, expected string
+		n           int
+	}{
+		{"short", "short", 10},
+		{"abcdef", "abc" + TruncationMarker, 3},
+		{"héllo", "h" + TruncationMarker, 2},
+	}
+	for _, test := range tests {
+		if s := truncate(test.s, test.n); s != test.expected {
+			t.Errorf("Incorrect truncation of %q. Expected %q, got %q", test.s, test.expected, s)
+		}
+	}
+}
+
+func TestConfigureLimits(t *testing.T) {
+	clearLoggers()
+	err := Configure([]byte(`{"loggers": {"app": {"handlers": {
+		"stdout": {"type": "stdout", "limits": {"max_message_length": 100, "max_fields": 10}}
+	}}}}`))
+	if err != nil {
+		t.Fatalf("Error configuring: %s", err.Error())
+	}
+	f := getLogger("app").GetHandler("stdout").(*StdoutHandler).formatter.(*LineFormatter)
+	if f.limits != (Limits{MaxMessageLength: 100, MaxFields: 10}) {
+		t.Errorf("Incorrect limits. Got %+v", f.limits)
+	}
+}
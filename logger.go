@@ -3,7 +3,10 @@
 
 package verbose
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
 // Fields type, used to pass to `WithFields`.
 type Fields map[string]interface{}
@@ -40,10 +43,10 @@ func removeLogger(l *Logger) {
 // already added. If path is "", the FileHandler is not added.
 // This is meant for convenience. The handlers use their default
 // min and max levels. The StdoutHandler is named "stdout" and the
-// FileHandler is named "file"
+// FileHandler is named "file". The StdoutHandler uses ColorModeAuto.
 func Classic(n, path string) (*Logger, error) {
 	l := New(n)
-	l.AddHandler("stdout", NewStdoutHandler(true))
+	l.AddHandler("stdout", NewStdoutHandlerWithColor(ColorModeAuto))
 	if path != "" {
 		f, err := NewFileHandler(path)
 		if err != nil {
@@ -54,11 +57,19 @@ func Classic(n, path string) (*Logger, error) {
 	return l, nil
 }
 
+// A Clock provides the current time. Loggers use it to timestamp entries.
+type Clock interface {
+	Now() time.Time
+}
+
 // A Logger takes a message and writes it to as many handlers as possible
 type Logger struct {
-	name     string
-	handlers map[string]Handler
-	m        sync.RWMutex
+	name      string
+	handlers  map[string]Handler
+	sampler   *sampleCounter
+	clock     Clock
+	redaction *Redaction
+	m         sync.RWMutex
 }
 
 // New will create a new Logger with name n. If with the same name
@@ -95,6 +106,16 @@ func (l *Logger) AddHandler(n string, h Handler) {
 	l.m.Unlock()
 }
 
+// replaceHandler adds Handler h to the logger as n and returns the handler
+// it replaced, if any.
+func (l *Logger) replaceHandler(n string, h Handler) Handler {
+	l.m.Lock()
+	defer l.m.Unlock()
+	old := l.handlers[n]
+	l.handlers[n] = h
+	return old
+}
+
 // GetHandler will return handler with name n or nil if it doesn't exist.
 func (l *Logger) GetHandler(n string) Handler {
 	if n == "" {
@@ -123,11 +144,80 @@ func (l *Logger) RemoveHandler(n string) {
 	}
 }
 
+// SetSampler sets a Sampler deciding which entries the logger writes.
+// Entries dropped by the sampler are never given to the handlers so they
+// skip formatting entirely. The number of dropped entries is reported every
+// report interval and when the sampler is replaced or the logger is closed.
+// If report is 0, drops aren't reported. A nil Sampler disables sampling.
+func (l *Logger) SetSampler(s Sampler, report time.Duration) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.stopSampler()
+	if s == nil {
+		l.sampler = nil
+		return
+	}
+	l.sampler = newSampleCounter(s, report, l.writeSampleReport)
+}
+
+func (l *Logger) writeSampleReport(report *Entry) {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	report.process()
+}
+
+// stopSampler stops the sampler's reports and writes the final one. The
+// logger lock must be held.
+func (l *Logger) stopSampler() {
+	if l.sampler == nil {
+		return
+	}
+	if report := l.sampler.stop(); report != nil {
+		report.process()
+	}
+}
+
+// SetRedaction sets a Redaction applied to every entry before any handler
+// sees it. A nil Redaction disables redaction.
+func (l *Logger) SetRedaction(r *Redaction) {
+	l.m.Lock()
+	l.redaction = r
+	l.m.Unlock()
+}
+
+// SetClock sets the Clock used to timestamp entries and time traced
+// functions. It's meant for tests and replaying logs. A nil Clock restores the
+// real clock.
+func (l *Logger) SetClock(c Clock) {
+	l.m.Lock()
+	l.clock = c
+	l.m.Unlock()
+}
+
+// Now returns the current time of the logger's clock.
+func (l *Logger) Now() time.Time {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	return l.now()
+}
+
+// now returns the current time of the logger's clock. The logger lock must
+// be held.
+func (l *Logger) now() time.Time {
+	if l.clock == nil {
+		return time.Now()
+	}
+	return l.clock.Now()
+}
+
 // Close calls Close() on all the handlers then removes itself from the logger registry
 func (l *Logger) Close() {
+	l.m.RLock()
+	l.stopSampler()
 	for _, h := range l.handlers {
 		h.Close()
 	}
+	l.m.RUnlock()
 	removeLogger(l)
 }
 
@@ -143,6 +233,23 @@ func (l *Logger) log(level LogLevel, msg string) {
 	e.log(level, msg)
 }
 
+// Log logs a message at level. It's meant for custom levels or when the
+// level is chosen at runtime. Unlike Fatal, it doesn't exit.
+func (l *Logger) Log(level LogLevel, v ...interface{}) {
+	NewEntry(l).Log(level, v...)
+}
+
+// Logf logs a formatted message at level. Unlike Fatalf, it doesn't exit.
+func (l *Logger) Logf(level LogLevel, m string, v ...interface{}) {
+	NewEntry(l).Logf(level, m, v...)
+}
+
+// Logln logs a message at level with spaces between operands. Unlike Fatalln,
+// it doesn't exit.
+func (l *Logger) Logln(level LogLevel, v ...interface{}) {
+	NewEntry(l).Logln(level, v...)
+}
+
 // WithField creates an Entry with a single field
 func (l *Logger) WithField(key string, value interface{}) *Entry {
 	return NewEntry(l).WithFields(Fields{key: value})
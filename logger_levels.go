@@ -2,11 +2,28 @@
 
 package verbose
 
-import "os"
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
 
 // LogLevel is used to compare levels in a consistant manner
 type LogLevel int
 
+// Won't compile if LogLevel doesn't satisfy these interfaces
+var (
+	_ flag.Value               = new(LogLevel)
+	_ encoding.TextMarshaler   = LogLevelDebug
+	_ encoding.TextUnmarshaler = new(LogLevel)
+	_ json.Marshaler           = LogLevelDebug
+	_ json.Unmarshaler         = new(LogLevel)
+)
+
 // String returns the stringified version of LogLevel.
 // I.e., "Error" for LogLevelError, and "Debug" for LogLevelDebug
 // It will return an empty string for any undefined level.
@@ -14,12 +31,13 @@ func (l LogLevel) String() string {
 	if s, ok := levelString[l]; ok {
 		return s
 	}
-	return ""
+	return customLevelName(l)
 }
 
 // These are the defined log levels
 const (
-	LogLevelDebug LogLevel = iota
+	LogLevelTrace LogLevel = iota - 1
+	LogLevelDebug
 	LogLevelInfo
 	LogLevelNotice
 	LogLevelWarning
@@ -31,7 +49,8 @@ const (
 )
 
 // LogLevel to stringified versions
-var levelString = map[LogLevel]string{ 
+var levelString = map[LogLevel]string{
+	LogLevelTrace:     "Trace",
 	LogLevelDebug:     "Debug",
 	LogLevelInfo:     "Info",
 	LogLevelNotice:     "Notice",
@@ -43,6 +62,91 @@ var levelString = map[LogLevel]string{
 	LogLevelFatal:     "Fatal",
 }
 
+// Lowercase names and aliases to LogLevel
+var levelNames = map[string]LogLevel{
+	"trace":     LogLevelTrace,
+	"debug":     LogLevelDebug,
+	"info":     LogLevelInfo,
+	"notice":     LogLevelNotice,
+	"warning":     LogLevelWarning,
+	"error":     LogLevelError,
+	"critical":     LogLevelCritical,
+	"alert":     LogLevelAlert,
+	"emergency":     LogLevelEmergency,
+	"fatal":     LogLevelFatal,
+	"warn":     LogLevelWarning,
+	"err":     LogLevelError,
+	"crit":     LogLevelCritical,
+	"emerg":     LogLevelEmergency,
+}
+
+// ParseLevel returns the LogLevel named s. Names are case insensitive and the
+// abbreviations "warn", "err", "crit", "emerg" are accepted.
+// The number of a level is also accepted. Custom levels are recognized once
+// they're registered.
+func ParseLevel(s string) (LogLevel, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if l, ok := levelNames[name]; ok {
+		return l, nil
+	}
+	if l, ok := lookupCustomLevel(name); ok {
+		return l, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil {
+		if LogLevel(n).String() != "" {
+			return LogLevel(n), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid log level %q", s)
+}
+
+// MarshalText returns the name of the level.
+func (l LogLevel) MarshalText() ([]byte, error) {
+	s := l.String()
+	if s == "" {
+		return nil, fmt.Errorf("invalid log level %d", int(l))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText sets the level using ParseLevel.
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalJSON returns the name of the level as a JSON string.
+func (l LogLevel) MarshalJSON() ([]byte, error) {
+	text, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON sets the level from a JSON string or number using ParseLevel.
+func (l *LogLevel) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("invalid log level %s", data)
+		}
+		s = strconv.Itoa(n)
+	}
+	return l.UnmarshalText([]byte(s))
+}
+
+// Set sets the level using ParseLevel. Along with String, this makes LogLevel
+// a flag.Value.
+func (l *LogLevel) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
 // Debug - Log Debug message
 func (l *Logger) Debug(v ...interface{}) {
     NewEntry(l).Debug(v...)
@@ -1,8 +1,11 @@
 package verbose
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"testing"
+	"time"
 )
 
 // testHandler is a special handler that will check for a correct LogLevel and message
@@ -140,3 +143,123 @@ func TestLoggingLevels(t *testing.T) {
 	logger.Debugf("%s %s", testMsg, LogLevelDebug.String())
 	logger.Debugln(testMsg, LogLevelDebug.String())
 }
+
+// TestLogGeneric checks the generic Log functions use the given level
+func TestLogGeneric(t *testing.T) {
+	clearLoggers()
+	testMsg := "The space ship is coming"
+	logger := New("logger1")
+	for _, level := range []LogLevel{LogLevelDebug, LogLevelWarning, LogLevelFatal} {
+		logger.AddHandler("h", newTestHandler(t, level, "logger1", testMsg, level.String()))
+
+		logger.Log(level, testMsg, " ", level.String())
+		logger.Logf(level, "%s %s", testMsg, level.String())
+		logger.Logln(level, testMsg, level.String())
+
+		e := logger.WithField("key", "value")
+		e.Log(level, testMsg, " ", level.String())
+		e.Logf(level, "%s %s", testMsg, level.String())
+		e.Logln(level, testMsg, level.String())
+	}
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestSetClock(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+
+	now := time.Date(2017, time.March, 4, 12, 0, 0, 0, time.UTC)
+	logger.SetClock(fixedClock(now))
+	logger.Info("fixed")
+	if !logger.Now().Equal(now) {
+		t.Errorf("Incorrect logger time. Expected %s, got %s", now, logger.Now())
+	}
+
+	logger.SetClock(nil)
+	logger.Info("real")
+
+	if len(rec.entries) != 2 {
+		t.Fatalf("Incorrect number of entries. Expected 2, got %d", len(rec.entries))
+	}
+	if !rec.entries[0].Timestamp.Equal(now) {
+		t.Errorf("Clock not used. Expected %s, got %s", now, rec.entries[0].Timestamp)
+	}
+	if rec.entries[1].Timestamp.Equal(now) {
+		t.Error("Real clock not restored")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]LogLevel{
+		"debug":     LogLevelDebug,
+		"INFO":      LogLevelInfo,
+		"Warning":   LogLevelWarning,
+		"warn":      LogLevelWarning,
+		" err ":     LogLevelError,
+		"crit":      LogLevelCritical,
+		"emerg":     LogLevelEmergency,
+		"fatal":     LogLevelFatal,
+		"3":         LogLevelWarning,
+		"Emergency": LogLevelEmergency,
+		"trace":     LogLevelTrace,
+		"-1":        LogLevelTrace,
+	}
+	for in, expected := range tests {
+		l, err := ParseLevel(in)
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", in, err.Error())
+			continue
+		}
+		if l != expected {
+			t.Errorf("Incorrect level for %q. Expected %d, got %d", in, expected, l)
+		}
+	}
+
+	for _, in := range []string{"", "loud", "42", "-2"} {
+		if _, err := ParseLevel(in); err == nil {
+			t.Errorf("Expected error parsing %q", in)
+		}
+	}
+}
+
+func TestLevelMarshal(t *testing.T) {
+	var c struct {
+		Level LogLevel `json:"level"`
+	}
+
+	data, err := json.Marshal(struct{ Level LogLevel }{LogLevelCritical})
+	if err != nil {
+		t.Fatalf("Error marshalling level: %s", err.Error())
+	}
+	if string(data) != `{"Level":"Critical"}` {
+		t.Errorf("Incorrect JSON. Expected {\"Level\":\"Critical\"}, got %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"level": "warn"}`), &c); err != nil || c.Level != LogLevelWarning {
+		t.Errorf("Incorrect level from JSON string. Expected %d, got %d (%v)", LogLevelWarning, c.Level, err)
+	}
+	if err := json.Unmarshal([]byte(`{"level": 1}`), &c); err != nil || c.Level != LogLevelInfo {
+		t.Errorf("Incorrect level from JSON number. Expected %d, got %d (%v)", LogLevelInfo, c.Level, err)
+	}
+	if err := json.Unmarshal([]byte(`{"level": "loud"}`), &c); err == nil {
+		t.Error("Expected error unmarshalling invalid level")
+	}
+	if _, err := LogLevel(42).MarshalText(); err == nil {
+		t.Error("Expected error marshalling undefined level")
+	}
+
+	level := LogLevelInfo
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&level, "level", "log level")
+	if err := fs.Parse([]string{"-level", "ERROR"}); err != nil {
+		t.Fatalf("Error parsing flags: %s", err.Error())
+	}
+	if level != LogLevelError {
+		t.Errorf("Incorrect level from flag. Expected %d, got %d", LogLevelError, level)
+	}
+}
@@ -0,0 +1,37 @@
+package verbose
+
+import "fmt"
+
+// maxLogValuerDepth limits how many LogValuers are resolved in a row in case
+// one returns itself.
+const maxLogValuerDepth = 100
+
+// A LogValuer is a field value that decides how it's logged. LogValue is
+// called by formatters when an entry is written, so expensive values are
+// only computed if a handler actually writes them. It may be called once for
+// each handler. If LogValue returns another LogValuer, it's resolved too.
+//
+//	func (u *User) LogValue() interface{} { return u.ID }
+type LogValuer interface {
+	LogValue() interface{}
+}
+
+// resolveValue returns the value v should be logged as. LogValuers are
+// resolved and any other value is returned as is. A panic in LogValue is
+// logged in place of the value.
+func resolveValue(v interface{}) (value interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			value = fmt.Sprintf("!PANIC(LogValue): %v", r)
+		}
+	}()
+
+	for i := 0; i < maxLogValuerDepth; i++ {
+		lv, ok := v.(LogValuer)
+		if !ok {
+			return v
+		}
+		v = lv.LogValue()
+	}
+	return v
+}
@@ -0,0 +1,164 @@
+package verbose
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testAccount struct {
+	ID    int
+	Email string
+}
+
+func (a *testAccount) LogValue() interface{} { return a.ID }
+
+type countingValuer struct {
+	calls int
+}
+
+func (c *countingValuer) LogValue() interface{} {
+	c.calls++
+	return "expensive"
+}
+
+type nestedValuer struct{}
+
+func (nestedValuer) LogValue() interface{} { return &testAccount{ID: 7} }
+
+type panicValuer struct{}
+
+func (panicValuer) LogValue() interface{} { panic("oops") }
+
+type loopValuer struct{}
+
+func (l loopValuer) LogValue() interface{} { return l }
+
+func TestResolveValue(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{42, 42},
+		{&testAccount{ID: 1, Email: "bob@example.com"}, 1},
+		{nestedValuer{}, 7},
+		{panicValuer{}, "!PANIC(LogValue): oops"},
+		{loopValuer{}, loopValuer{}},
+	}
+	for _, test := range tests {
+		if v := resolveValue(test.value); v != test.expected {
+			t.Errorf("Incorrect value for %T. Expected %v, got %v", test.value, test.expected, v)
+		}
+	}
+}
+
+func TestLogValuerFormatters(t *testing.T) {
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelInfo
+	e.Message = "Logged in"
+	e.Timestamp = time.Now()
+	e.Data = Fields{"account": &testAccount{ID: 42, Email: "bob@example.com"}}
+
+	formatters := []Formatter{
+		NewJSONFormatter(),
+		NewLineFormatter(),
+		NewColoredLineFormatter(),
+		NewPrettyFormatter(false),
+	}
+	for _, f := range formatters {
+		result := f.Format(e)
+		if !strings.Contains(result, "42") || strings.Contains(result, "bob@example.com") {
+			t.Errorf("LogValue not used by %T. Got `%s`", f, result)
+		}
+	}
+
+	var gelf map[string]interface{}
+	if err := json.Unmarshal(NewGELFFormatter().FormatByte(e), &gelf); err != nil {
+		t.Fatalf("Invalid GELF: %s", err.Error())
+	}
+	if gelf["_account"] != float64(42) {
+		t.Errorf("LogValue not used by GELFFormatter. Got %v", gelf["_account"])
+	}
+}
+
+func TestLogValuerLazy(t *testing.T) {
+	clearLoggers()
+	logger := New("logger")
+	sh := NewStdoutHandler(false)
+	sh.out = &strings.Builder{}
+	sh.SetMinLevel(LogLevelInfo)
+	logger.AddHandler("stdout", sh)
+
+	v := &countingValuer{}
+	logger.WithField("value", v).Debug("Not written")
+	if v.calls != 0 {
+		t.Errorf("LogValue called for an entry that wasn't written")
+	}
+	logger.WithField("value", v).Info("Written")
+	if v.calls != 1 {
+		t.Errorf("Incorrect number of LogValue calls. Expected 1, got %d", v.calls)
+	}
+}
+
+type secretValuer struct {
+	calls int
+}
+
+func (s *secretValuer) LogValue() interface{} {
+	s.calls++
+	return "mail bob@example.com"
+}
+
+func TestLogValuerLazyRedaction(t *testing.T) {
+	clearLoggers()
+	logger := New("logger")
+	out := &strings.Builder{}
+	sh := NewStdoutHandler(false)
+	sh.out = out
+	sh.SetMinLevel(LogLevelInfo)
+	logger.AddHandler("stdout", sh)
+	logger.SetRedaction(&Redaction{
+		Keys:     []string{"token"},
+		Patterns: []*regexp.Regexp{RedactEmailPattern},
+	})
+
+	v, token := &secretValuer{}, &countingValuer{}
+	logger.WithFields(Fields{"value": v, "token": token}).Debug("Not written")
+	if v.calls != 0 || token.calls != 0 {
+		t.Errorf("LogValue called for an entry that wasn't written")
+	}
+
+	logger.WithFields(Fields{"value": v, "token": token}).Info("Written")
+	if v.calls != 1 || token.calls != 1 {
+		t.Errorf("Incorrect number of LogValue calls. Expected 1, got %d and %d", v.calls, token.calls)
+	}
+	if s := out.String(); strings.Contains(s, "bob@example.com") || strings.Contains(s, "expensive") {
+		t.Errorf("Resolved values not redacted. Got %s", s)
+	}
+}
+
+func TestLogValuerLimits(t *testing.T) {
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelInfo
+	e.Message = "Limited"
+	e.Timestamp = time.Now()
+
+	formatters := []Formatter{
+		NewJSONFormatter(),
+		NewLineFormatter(),
+		NewGELFFormatter(),
+	}
+	for _, f := range formatters {
+		for _, max := range []int{5, 20} {
+			v := &countingValuer{}
+			e.Data = Fields{"value": v}
+			f.(Limiter).SetLimits(Limits{MaxFieldValueLength: max})
+			f.Format(e)
+			if v.calls != 1 {
+				t.Errorf("Incorrect number of LogValue calls by %T with limit %d. Expected 1, got %d", f, max, v.calls)
+			}
+		}
+	}
+}
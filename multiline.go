@@ -0,0 +1,113 @@
+package verbose
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// MultilineMode decides how line formatters write messages and field values
+// containing newlines.
+type MultilineMode int
+
+// Multiline modes
+const (
+	// MultilineVerbatim writes newlines as is. An entry may span several
+	// lines. This is the default.
+	MultilineVerbatim MultilineMode = iota
+	// MultilineEscape replaces newlines with \n so every entry is one line.
+	MultilineEscape
+	// MultilineIndent starts continuation lines with the multiline prefix.
+	MultilineIndent
+	// MultilineSplit writes each line of the message as its own line with the
+	// timestamp, level and logger. Fields are written on the first line with
+	// newlines escaped.
+	MultilineSplit
+)
+
+// DefaultMultilinePrefix is the prefix of continuation lines in
+// MultilineIndent mode.
+const DefaultMultilinePrefix = "    "
+
+var multilineModes = map[string]MultilineMode{
+	"verbatim": MultilineVerbatim,
+	"escape":   MultilineEscape,
+	"indent":   MultilineIndent,
+	"split":    MultilineSplit,
+}
+
+// ParseMultilineMode returns the MultilineMode named s, "verbatim",
+// "escape", "indent" or "split".
+func ParseMultilineMode(s string) (MultilineMode, error) {
+	if m, ok := multilineModes[strings.ToLower(s)]; ok {
+		return m, nil
+	}
+	return MultilineVerbatim, fmt.Errorf("unknown multiline mode %q", s)
+}
+
+func (m MultilineMode) String() string {
+	for s, mode := range multilineModes {
+		if mode == m {
+			return s
+		}
+	}
+	return fmt.Sprintf("MultilineMode(%d)", int(m))
+}
+
+var newlineEscaper = strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\r`)
+
+// multiline holds the multiline settings of a line formatter. It's embedded
+// in the line formatters to provide SetMultilineMode and SetMultilinePrefix.
+type multiline struct {
+	mode   MultilineMode
+	prefix string
+}
+
+func newMultiline() multiline {
+	return multiline{prefix: DefaultMultilinePrefix}
+}
+
+// SetMultilineMode sets how messages and field values with newlines are
+// written.
+func (m *multiline) SetMultilineMode(mode MultilineMode) {
+	m.mode = mode
+}
+
+// SetMultilinePrefix sets the prefix of continuation lines in
+// MultilineIndent mode.
+func (m *multiline) SetMultilinePrefix(p string) {
+	m.prefix = p
+}
+
+// writeLines writes a formatted entry to buf. Header is written before the
+// message and msgEnd after it, fields ends the entry.
+func (m *multiline) writeLines(buf *bytes.Buffer, header, msg, msgEnd, fields string) {
+	switch m.mode {
+	case MultilineEscape:
+		msg = newlineEscaper.Replace(msg)
+		fields = newlineEscaper.Replace(fields)
+	case MultilineIndent:
+		indent := strings.NewReplacer("\r\n", "\n"+m.prefix, "\n", "\n"+m.prefix)
+		msg = indent.Replace(strings.TrimRight(msg, "\r\n"))
+		fields = indent.Replace(fields)
+	case MultilineSplit:
+		lines := strings.Split(strings.TrimRight(msg, "\r\n"), "\n")
+		fields = newlineEscaper.Replace(fields)
+		for i, line := range lines {
+			buf.WriteString(header)
+			buf.WriteString(strings.TrimSuffix(line, "\r"))
+			buf.WriteString(msgEnd)
+			if i == 0 {
+				buf.WriteString(fields)
+			}
+			buf.WriteByte('\n')
+		}
+		return
+	}
+
+	buf.WriteString(header)
+	buf.WriteString(msg)
+	buf.WriteString(msgEnd)
+	buf.WriteString(fields)
+	buf.WriteByte('\n')
+}
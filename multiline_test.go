@@ -0,0 +1,68 @@
+package verbose
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultilineModes(t *testing.T) {
+	now := time.Now()
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelError
+	e.Message = "panic: boom\ngoroutine 1\r\nmain.main()\n"
+	e.Timestamp = now
+	e.Data = Fields{"stack": "a\nb"}
+	header := fmt.Sprintf("%s: ERROR: logger: ", now.Format(time.RFC3339))
+
+	tests := []struct {
+		mode     MultilineMode
+		expected string
+	}{
+		{MultilineVerbatim, header + "panic: boom\ngoroutine 1\r\nmain.main()\n" + ` | "stack": "a` + "\n" + `b"` + "\n"},
+		{MultilineEscape, header + `panic: boom\ngoroutine 1\nmain.main()\n | "stack": "a\nb"` + "\n"},
+		{MultilineIndent, header + "panic: boom\n> goroutine 1\n> main.main()" + ` | "stack": "a` + "\n> " + `b"` + "\n"},
+		{MultilineSplit, header + `panic: boom | "stack": "a\nb"` + "\n" + header + "goroutine 1\n" + header + "main.main()\n"},
+	}
+
+	for _, test := range tests {
+		formatter := NewLineFormatter()
+		formatter.SetMultilineMode(test.mode)
+		formatter.SetMultilinePrefix("> ")
+		if result := formatter.Format(e); result != test.expected {
+			t.Errorf("Incorrect %s output. Expected %q, got %q", test.mode, test.expected, result)
+		}
+	}
+}
+
+func TestMultilineColored(t *testing.T) {
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelInfo
+	e.Message = "one\ntwo"
+	e.Timestamp = time.Now()
+
+	formatter := NewColoredLineFormatter()
+	formatter.SetMultilineMode(MultilineSplit)
+	lines := strings.Split(strings.TrimSuffix(formatter.Format(e), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Incorrect number of lines. Expected 2, got %d", len(lines))
+	}
+	for i, msg := range []string{"one", "two"} {
+		if !strings.HasPrefix(lines[i], string(ColorGrey)) || !strings.HasSuffix(lines[i], string(ColorReset)+": "+msg) {
+			t.Errorf("Incorrect line %d. Got %q", i, lines[i])
+		}
+	}
+}
+
+func TestParseMultilineMode(t *testing.T) {
+	for _, mode := range []MultilineMode{MultilineVerbatim, MultilineEscape, MultilineIndent, MultilineSplit} {
+		m, err := ParseMultilineMode(strings.ToUpper(mode.String()))
+		if err != nil || m != mode {
+			t.Errorf("Incorrect mode for %q. Expected %d, got %d (%v)", mode.String(), mode, m, err)
+		}
+	}
+	if _, err := ParseMultilineMode("wrap"); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+}
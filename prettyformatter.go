@@ -0,0 +1,190 @@
+package verbose
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PrettyFormatter defaults
+const (
+	prettyTimeFormat  = "15:04:05.000"
+	prettyLevelWidth  = len("EMERGENCY")
+	prettyLoggerWidth = 10
+	prettyMaxValueLen = 120
+	prettyFieldIndent = "    "
+	prettyElided      = "…"
+)
+
+// PrettyFormatter is meant for reading logs in a terminal during development.
+// Each entry starts with the time, level and logger name in aligned columns
+// followed by the message. Fields are written sorted by key, either each on
+// its own indented line or inline after the message as key=value pairs.
+// Continuation lines of multi-line messages and values, such as stack
+// traces, are indented. Long single line values are elided.
+type PrettyFormatter struct {
+	limiter
+	timeFormat   string
+	color        bool
+	theme        Theme
+	inlineFields bool
+	loggerWidth  int
+	maxValueLen  int
+}
+
+// NewPrettyFormatter creates a PrettyFormatter. Color specifies if the
+// output uses the colors of the default theme.
+func NewPrettyFormatter(color bool) *PrettyFormatter {
+	theme := DefaultTheme()
+	theme.FieldKey = ColorCyan
+	return &PrettyFormatter{
+		timeFormat:  prettyTimeFormat,
+		color:       color,
+		theme:       theme,
+		loggerWidth: prettyLoggerWidth,
+		maxValueLen: prettyMaxValueLen,
+	}
+}
+
+func (p *PrettyFormatter) Format(e *Entry) string {
+	return string(p.FormatByte(e))
+}
+
+func (p *PrettyFormatter) FormatByte(e *Entry) []byte {
+	return p.format(e, p.formatByte)
+}
+
+func (p *PrettyFormatter) formatByte(e *Entry) []byte {
+	buf := &bytes.Buffer{}
+
+	p.writeColored(buf, p.theme.Timestamp, e.Timestamp.Format(p.timeFormat))
+	buf.WriteByte(' ')
+	level := strings.ToUpper(e.Level.String())
+	p.writeColored(buf, p.theme.levelColor(e.Level), padRight(level, prettyLevelWidth))
+	buf.WriteByte(' ')
+	p.writeColored(buf, p.theme.Logger, padRight(e.Logger.Name(), p.loggerWidth))
+	buf.WriteByte(' ')
+
+	// Continuation lines of the message line up with its first line
+	indent := utf8.RuneCountInString(e.Timestamp.Format(p.timeFormat)) + prettyLevelWidth + 3
+	if n := utf8.RuneCountInString(e.Logger.Name()); n > p.loggerWidth {
+		indent += n
+	} else {
+		indent += p.loggerWidth
+	}
+	p.writeColored(buf, p.theme.Message, indentLines(e.Message, strings.Repeat(" ", indent)))
+
+	keys := make([]string, 0, len(e.Data))
+	keyWidth := 0
+	for k := range e.Data {
+		keys = append(keys, k)
+		if n := utf8.RuneCountInString(k); n > keyWidth {
+			keyWidth = n
+		}
+	}
+	sort.Strings(keys)
+
+	if p.inlineFields {
+		for _, k := range keys {
+			buf.WriteByte(' ')
+			p.writeColored(buf, p.theme.FieldKey, k)
+			buf.WriteByte('=')
+			p.writeColored(buf, p.theme.FieldValue, p.inlineValue(e.Data[k]))
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes()
+	}
+
+	buf.WriteByte('\n')
+	valueIndent := strings.Repeat(" ", len(prettyFieldIndent)+keyWidth+2)
+	for _, k := range keys {
+		buf.WriteString(prettyFieldIndent)
+		p.writeColored(buf, p.theme.FieldKey, k+":")
+		buf.WriteString(strings.Repeat(" ", keyWidth-utf8.RuneCountInString(k)+1))
+		p.writeColored(buf, p.theme.FieldValue, indentLines(p.value(e.Data[k]), valueIndent))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func (p *PrettyFormatter) SetTimeFormat(f string) {
+	p.timeFormat = f
+}
+
+// SetTheme sets the colors used by the formatter if color is enabled.
+func (p *PrettyFormatter) SetTheme(t Theme) {
+	p.theme = t
+}
+
+// SetInlineFields writes fields as key=value pairs after the message instead
+// of on their own lines.
+func (p *PrettyFormatter) SetInlineFields(inline bool) {
+	p.inlineFields = inline
+}
+
+// SetLoggerWidth sets the width of the logger name column. Longer names
+// aren't cut.
+func (p *PrettyFormatter) SetLoggerWidth(n int) {
+	p.loggerWidth = n
+}
+
+// SetMaxValueLength sets the number of characters after which single line
+// field values are elided. If n is 0, values are never elided.
+func (p *PrettyFormatter) SetMaxValueLength(n int) {
+	p.maxValueLen = n
+}
+
+// value returns the string representation of field value v. Single line
+// values longer than the maximum length are elided. Errors are written with
+// their type, causes and stack trace on separate lines.
+func (p *PrettyFormatter) value(v interface{}) string {
+	v = resolveValue(v)
+	if err, ok := v.(error); ok {
+		return errorDetail(err)
+	}
+	s := fmt.Sprintf("%v", v)
+	if p.maxValueLen <= 0 || strings.Contains(s, "\n") || utf8.RuneCountInString(s) <= p.maxValueLen {
+		return s
+	}
+	return string([]rune(s)[:p.maxValueLen]) + prettyElided
+}
+
+// inlineValue returns field value v for a key=value pair. Values that would
+// be ambiguous, such as ones with spaces, are quoted.
+func (p *PrettyFormatter) inlineValue(v interface{}) string {
+	s := fieldString(v)
+	if p.maxValueLen > 0 && utf8.RuneCountInString(s) > p.maxValueLen {
+		s = string([]rune(s)[:p.maxValueLen]) + prettyElided
+	}
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func (p *PrettyFormatter) writeColored(buf *bytes.Buffer, c Color, s string) {
+	if !p.color {
+		buf.WriteString(s)
+		return
+	}
+	writeColored(buf, c, s)
+}
+
+// padRight pads s with spaces to width characters.
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// indentLines prefixes every line of s but the first with indent.
+func indentLines(s, indent string) string {
+	if !strings.Contains(s, "\n") {
+		return s
+	}
+	return strings.Replace(strings.TrimRight(s, "\n"), "\n", "\n"+indent, -1)
+}
@@ -0,0 +1,69 @@
+package verbose
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newPrettyTestEntry() *Entry {
+	e := NewEntry(&Logger{name: "app"})
+	e.Level = LogLevelWarning
+	e.Message = "Disk almost full\nOnly 1% left"
+	e.Timestamp = time.Date(2017, time.March, 4, 12, 30, 15, 0, time.UTC)
+	e.Data = Fields{
+		"disk":  "/dev/sda1",
+		"stack": "main.check()\n\tmain.go:10",
+		"id":    "0123456789abcdef",
+	}
+	return e
+}
+
+func TestPrettyFormatter(t *testing.T) {
+	formatter := NewPrettyFormatter(false)
+	formatter.SetMaxValueLength(10)
+
+	expected := strings.Join([]string{
+		"12:30:15.000 WARNING   app        Disk almost full",
+		"                                  Only 1% left",
+		"    disk:  /dev/sda1",
+		"    id:    0123456789…",
+		"    stack: main.check()",
+		"           \tmain.go:10",
+		"",
+	}, "\n")
+	if result := formatter.Format(newPrettyTestEntry()); result != expected {
+		t.Errorf("Incorrectly formatted message. Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
+
+func TestPrettyFormatterInline(t *testing.T) {
+	formatter := NewPrettyFormatter(false)
+	formatter.SetInlineFields(true)
+	formatter.SetLoggerWidth(0)
+	formatter.SetTimeFormat(time.Kitchen)
+
+	e := newPrettyTestEntry()
+	e.Message = "Disk almost full"
+	expected := `12:30PM WARNING   app Disk almost full disk=/dev/sda1 id=0123456789abcdef stack="main.check()\n\tmain.go:10"` + "\n"
+	if result := formatter.Format(e); result != expected {
+		t.Errorf("Incorrectly formatted message. Expected `%s`, got `%s`", expected, result)
+	}
+}
+
+func TestPrettyFormatterColor(t *testing.T) {
+	formatter := NewPrettyFormatter(true)
+	formatter.SetInlineFields(true)
+
+	result := formatter.Format(newPrettyTestEntry())
+	for _, s := range []string{
+		string(ColorGrey) + "12:30:15.000" + string(ColorReset),
+		string(colors[LogLevelWarning]) + "WARNING  " + string(ColorReset),
+		string(ColorGreen) + "app       " + string(ColorReset),
+		string(ColorCyan) + "disk" + string(ColorReset) + "=",
+	} {
+		if !strings.Contains(result, s) {
+			t.Errorf("Colored output doesn't contain %q. Got %q", s, result)
+		}
+	}
+}
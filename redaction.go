@@ -0,0 +1,222 @@
+package verbose
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactMode decides how redacted values are masked.
+type RedactMode int
+
+// Redaction modes
+const (
+	// RedactFull replaces the value with RedactedValue.
+	RedactFull RedactMode = iota
+	// RedactPartial keeps the last 4 characters of pattern matches longer
+	// than 8 characters, e.g. "****1234". Shorter matches and fields matched
+	// by key are fully redacted.
+	RedactPartial
+	// RedactHash replaces the value with a SHA-256 hash so equal values can
+	// be correlated without being revealed. Set Redaction.HashKey to use an
+	// HMAC, short values such as PINs can otherwise be guessed from the hash.
+	RedactHash
+)
+
+// RedactedValue replaces fully redacted values.
+const RedactedValue = "[REDACTED]"
+
+// Common sensitive values. They can be used in Redaction.Patterns.
+var (
+	RedactCreditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	RedactEmailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	RedactBearerPattern     = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+)
+
+// DefaultRedactKeys are commonly sensitive field keys.
+var DefaultRedactKeys = []string{"password", "passwd", "secret", "token", "authorization", "api_key", "apikey"}
+
+// A Redactor is implemented by values that know how to redact themselves.
+// Redact returns the value to log in place of the original.
+type Redactor interface {
+	Redact() interface{}
+}
+
+// Redaction masks sensitive data in entries before they're written. Fields
+// whose key matches one of Keys, ignoring case, are replaced by RedactedValue,
+// or by a hash in RedactHash mode. Matches
+// of Patterns are masked in the message and in string, error and
+// fmt.Stringer field values. Field values implementing Redactor are replaced
+// by their Redact value. LogValuers are redacted once a formatter resolves
+// them, and nested Fields and map[string]interface{} values are redacted too.
+//
+// A Redaction can be set on a Logger with SetRedaction, or on a single
+// handler with NewRedactingHandler. It must not be changed once in use.
+type Redaction struct {
+	Keys     []string
+	Patterns []*regexp.Regexp
+	Mode     RedactMode
+	HashKey  []byte
+}
+
+// Apply returns a redacted copy of e. The original entry isn't changed.
+func (r *Redaction) Apply(e *Entry) *Entry {
+	c := e.Clone()
+	c.Message = r.redactString(c.Message)
+	c.Data = r.redactFields(c.Data)
+	return c
+}
+
+func (r *Redaction) redactFields(f map[string]interface{}) Fields {
+	redacted := make(Fields, len(f))
+	for k, v := range f {
+		if r.redactKey(k) {
+			if lv, ok := v.(LogValuer); ok {
+				redacted[k] = redactedValuer{r: r, v: lv, key: true}
+			} else {
+				redacted[k] = r.maskKey(fmt.Sprintf("%v", v))
+			}
+			continue
+		}
+		redacted[k] = r.redactValue(v)
+	}
+	return redacted
+}
+
+func (r *Redaction) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case Redactor:
+		return v.Redact()
+	case LogValuer:
+		return redactedValuer{r: r, v: v}
+	case string:
+		return r.redactString(v)
+	case Fields:
+		return r.redactFields(v)
+	case map[string]interface{}:
+		return map[string]interface{}(r.redactFields(v))
+	case error:
+		if s := v.Error(); r.matches(s) {
+			return r.redactString(s)
+		}
+	case fmt.Stringer:
+		if s := v.String(); r.matches(s) {
+			return r.redactString(s)
+		}
+	}
+	return v
+}
+
+func (r *Redaction) redactKey(k string) bool {
+	for _, key := range r.Keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redaction) matches(s string) bool {
+	for _, p := range r.Patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redaction) redactString(s string) string {
+	for _, p := range r.Patterns {
+		s = p.ReplaceAllStringFunc(s, r.mask)
+	}
+	return s
+}
+
+// maskKey returns the value of a field matched by key. Unlike pattern
+// matches, none of it is kept in RedactPartial mode.
+func (r *Redaction) maskKey(s string) string {
+	if r.Mode == RedactHash {
+		return r.mask(s)
+	}
+	return RedactedValue
+}
+
+// mask returns s masked according to the redaction mode.
+func (r *Redaction) mask(s string) string {
+	switch r.Mode {
+	case RedactPartial:
+		n := utf8.RuneCountInString(s)
+		if n <= 8 {
+			return RedactedValue
+		}
+		return "****" + string([]rune(s)[n-4:])
+	case RedactHash:
+		if len(r.HashKey) > 0 {
+			mac := hmac.New(sha256.New, r.HashKey)
+			mac.Write([]byte(s))
+			return "hmac:" + hex.EncodeToString(mac.Sum(nil))[:16]
+		}
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:])[:16]
+	}
+	return RedactedValue
+}
+
+// redactedValuer redacts the value of a LogValuer when it's resolved so
+// redaction doesn't compute values of entries that are never written.
+type redactedValuer struct {
+	r   *Redaction
+	v   LogValuer
+	key bool // The field's key matched
+}
+
+func (rv redactedValuer) LogValue() interface{} {
+	v := resolveValue(rv.v)
+	if rv.key {
+		return rv.r.maskKey(fmt.Sprintf("%v", v))
+	}
+	return rv.r.redactValue(v)
+}
+
+// RedactingHandler wraps a handler and redacts entries before giving them
+// to it.
+type RedactingHandler struct {
+	levelRange
+	handler   Handler
+	redaction *Redaction
+}
+
+// NewRedactingHandler creates a RedactingHandler that writes entries
+// redacted by r to h.
+func NewRedactingHandler(h Handler, r *Redaction) *RedactingHandler {
+	return &RedactingHandler{
+		levelRange: newLevelRange(),
+		handler:    h,
+		redaction:  r,
+	}
+}
+
+// SetFormatter sets the formatter of the wrapped handler.
+func (r *RedactingHandler) SetFormatter(f Formatter) {
+	r.handler.SetFormatter(f)
+}
+
+// Handles returns whether both the handler and the wrapped handler handle
+// log level l.
+func (r *RedactingHandler) Handles(l LogLevel) bool {
+	return r.levelRange.Handles(l) && r.handler.Handles(l)
+}
+
+// WriteLog writes a redacted copy of the entry to the wrapped handler.
+func (r *RedactingHandler) WriteLog(e *Entry) {
+	r.handler.WriteLog(r.redaction.Apply(e))
+}
+
+// Close closes the wrapped handler.
+func (r *RedactingHandler) Close() {
+	r.handler.Close()
+}
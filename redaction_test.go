@@ -0,0 +1,134 @@
+package verbose
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type testUser struct {
+	ID       int
+	Password string
+}
+
+func (u testUser) Redact() interface{} { return u.ID }
+
+func TestRedactionApply(t *testing.T) {
+	r := &Redaction{
+		Keys:     []string{"password", "Authorization"},
+		Patterns: []*regexp.Regexp{RedactCreditCardPattern, RedactEmailPattern, RedactBearerPattern},
+	}
+
+	e := NewEntry(&Logger{name: "logger"})
+	e.Message = "Charged 4111 1111 1111 1111 for bob@example.com"
+	e.Data = Fields{
+		"PASSWORD":      "hunter2",
+		"authorization": "Basic abc",
+		"header":        "Bearer abc.def-ghi",
+		"user":          testUser{ID: 42, Password: "hunter2"},
+		"err":           errors.New("no account for bob@example.com"),
+		"count":         3,
+		"nested":        Fields{"password": "hunter2", "ok": "fine"},
+	}
+
+	c := r.Apply(e)
+	if c.Message != "Charged "+RedactedValue+" for "+RedactedValue {
+		t.Errorf("Message not redacted. Got `%s`", c.Message)
+	}
+
+	expected := map[string]interface{}{
+		"PASSWORD":      RedactedValue,
+		"authorization": RedactedValue,
+		"header":        RedactedValue,
+		"user":          42,
+		"err":           "no account for " + RedactedValue,
+		"count":         3,
+	}
+	for k, v := range expected {
+		if c.Data[k] != v {
+			t.Errorf("Incorrect value for %s. Expected %v, got %v", k, v, c.Data[k])
+		}
+	}
+	nested := c.Data["nested"].(Fields)
+	if nested["password"] != RedactedValue || nested["ok"] != "fine" {
+		t.Errorf("Nested fields not redacted. Got %v", nested)
+	}
+
+	if e.Data["PASSWORD"] != "hunter2" || !strings.Contains(e.Message, "4111") {
+		t.Error("Original entry was changed")
+	}
+	if e.Data["nested"].(Fields)["password"] != "hunter2" {
+		t.Error("Original nested fields were changed")
+	}
+}
+
+func TestRedactionModes(t *testing.T) {
+	r := &Redaction{Mode: RedactPartial}
+	if m := r.mask("4111111111111234"); m != "****1234" {
+		t.Errorf("Incorrect partial mask. Expected ****1234, got %s", m)
+	}
+	if m := r.mask("short"); m != RedactedValue {
+		t.Errorf("Short values should be fully masked. Got %s", m)
+	}
+
+	r.Keys = []string{"password"}
+	r.Patterns = []*regexp.Regexp{RedactCreditCardPattern}
+	e := NewEntry(&Logger{name: "logger"})
+	e.Message = "Charged 4111 1111 1111 1234"
+	e.Data = Fields{"password": "correct horse battery staple"}
+	c := r.Apply(e)
+	if c.Message != "Charged ****1234" {
+		t.Errorf("Incorrect partial message mask. Got %s", c.Message)
+	}
+	if c.Data["password"] != RedactedValue {
+		t.Errorf("Fields matched by key should be fully masked. Got %v", c.Data["password"])
+	}
+
+	r = &Redaction{Mode: RedactHash}
+	h1, h2 := r.mask("secret"), r.mask("secret")
+	if !strings.HasPrefix(h1, "sha256:") || h1 != h2 || h1 == r.mask("other") {
+		t.Errorf("Incorrect hash mask. Got %s, %s", h1, h2)
+	}
+
+	r.HashKey = []byte("key")
+	if m := r.mask("secret"); !strings.HasPrefix(m, "hmac:") || m[5:] == h1[7:] {
+		t.Errorf("Incorrect HMAC mask. Got %s", m)
+	}
+}
+
+func TestLoggerRedaction(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+	logger.SetRedaction(&Redaction{Keys: DefaultRedactKeys})
+
+	fields := Fields{"token": "abc123"}
+	logger.WithFields(fields).Info("Logged in")
+	if rec.entries[0].Data["token"] != RedactedValue {
+		t.Errorf("Field not redacted. Got %v", rec.entries[0].Data)
+	}
+	if fields["token"] != "abc123" {
+		t.Error("Caller's fields were changed")
+	}
+
+	logger.SetRedaction(nil)
+	logger.WithFields(fields).Info("Logged in")
+	if rec.entries[1].Data["token"] != "abc123" {
+		t.Error("Redaction not disabled")
+	}
+}
+
+func TestRedactingHandler(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	plain := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("redacted", NewRedactingHandler(rec, &Redaction{Patterns: []*regexp.Regexp{RedactEmailPattern}}))
+	logger.AddHandler("plain", plain)
+
+	logger.Info("Mail sent to bob@example.com")
+	checkMessages(t, []string{"Mail sent to " + RedactedValue}, rec.messages())
+	checkMessages(t, []string{"Mail sent to bob@example.com"}, plain.messages())
+}
@@ -0,0 +1,207 @@
+package verbose
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// DefaultRingBufferMaxKeys is the default number of buffers a
+// RingBufferHandler keeps before discarding the least recently used one.
+const DefaultRingBufferMaxKeys = 1000
+
+// RingBufferHandler keeps the last entries of every level in memory and
+// writes nothing until an entry at or above the trigger level arrives. The
+// buffered entries and the triggering entry are then written to the wrapped
+// handler. This gives full debug context around errors without the cost of
+// always writing debug logs.
+//
+// By default entries are buffered per logger. Use SetKeyFunc to buffer by
+// something else such as a request ID. At most DefaultRingBufferMaxKeys
+// buffers are kept, the least recently used buffer is discarded when a new
+// one is needed. Use SetMaxKeys to change the limit.
+type RingBufferHandler struct {
+	levelRange
+	handler Handler
+	size    int
+	trigger LogLevel
+	keyFunc func(*Entry) string
+	maxKeys int
+	buffers map[string]*entryRing
+	lru     *list.List // Keys of buffers, most recently used first
+	m       sync.Mutex
+}
+
+// NewRingBufferHandler creates a RingBufferHandler keeping the last size
+// entries and flushing them to h when an entry at or above trigger is logged.
+func NewRingBufferHandler(h Handler, size int, trigger LogLevel) *RingBufferHandler {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBufferHandler{
+		levelRange: newLevelRange(),
+		handler:    h,
+		size:       size,
+		trigger:    trigger,
+		keyFunc:    loggerNameKey,
+		maxKeys:    DefaultRingBufferMaxKeys,
+		buffers:    make(map[string]*entryRing),
+		lru:        list.New(),
+	}
+}
+
+func loggerNameKey(e *Entry) string {
+	return e.Logger.Name()
+}
+
+// SetKeyFunc sets the function used to pick which buffer an entry goes in.
+// Entries with the same key share a buffer and are flushed together.
+func (r *RingBufferHandler) SetKeyFunc(f func(*Entry) string) {
+	if f == nil {
+		f = loggerNameKey
+	}
+	r.m.Lock()
+	r.keyFunc = f
+	r.m.Unlock()
+}
+
+// SetKeyField buffers entries by the value of field key, for example a
+// request ID. Entries without the field are buffered by logger name. The
+// buffer key is "key=value", e.g. "request=42", for use with Clear.
+func (r *RingBufferHandler) SetKeyField(key string) {
+	r.SetKeyFunc(func(e *Entry) string {
+		if v, ok := e.Data[key]; ok {
+			return key + "=" + fmt.Sprint(v)
+		}
+		return loggerNameKey(e)
+	})
+}
+
+// SetMaxKeys sets the number of buffers kept. When a new buffer is needed
+// and there are already n, the least recently used buffer is discarded
+// without being written. If n is 0, the number of buffers isn't limited.
+func (r *RingBufferHandler) SetMaxKeys(n int) {
+	r.m.Lock()
+	r.maxKeys = n
+	r.evict()
+	r.m.Unlock()
+}
+
+// SetFormatter sets the formatter of the wrapped handler.
+func (r *RingBufferHandler) SetFormatter(f Formatter) {
+	r.handler.SetFormatter(f)
+}
+
+// WriteLog buffers the entry or, if it's at or above the trigger level,
+// writes the buffered entries and e to the wrapped handler.
+func (r *RingBufferHandler) WriteLog(e *Entry) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	key := r.keyFunc(e)
+	buf, ok := r.buffers[key]
+	if e.Level < r.trigger {
+		if ok {
+			r.lru.MoveToFront(buf.elem)
+		} else {
+			buf = newEntryRing(r.size)
+			buf.elem = r.lru.PushFront(key)
+			r.buffers[key] = buf
+			r.evict()
+		}
+		buf.push(e.Clone())
+		return
+	}
+
+	if ok {
+		r.write(buf.drain())
+		r.remove(key)
+	}
+	r.write([]*Entry{e})
+}
+
+// evict discards the least recently used buffers until there are at most
+// maxKeys.
+func (r *RingBufferHandler) evict() {
+	for r.maxKeys > 0 && len(r.buffers) > r.maxKeys {
+		r.remove(r.lru.Back().Value.(string))
+	}
+}
+
+func (r *RingBufferHandler) remove(key string) {
+	if buf, ok := r.buffers[key]; ok {
+		r.lru.Remove(buf.elem)
+		delete(r.buffers, key)
+	}
+}
+
+func (r *RingBufferHandler) write(entries []*Entry) {
+	for _, e := range entries {
+		if r.handler.Handles(e.Level) {
+			r.handler.WriteLog(e)
+		}
+	}
+}
+
+// Flush writes all buffered entries to the wrapped handler regardless of
+// the trigger level.
+func (r *RingBufferHandler) Flush() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	for key, buf := range r.buffers {
+		r.write(buf.drain())
+		r.remove(key)
+	}
+}
+
+// Clear discards the buffered entries for key without writing them. This
+// should be called when a request finishes if entries are buffered per
+// request.
+func (r *RingBufferHandler) Clear(key string) {
+	r.m.Lock()
+	r.remove(key)
+	r.m.Unlock()
+}
+
+// Close discards any buffered entries and closes the wrapped handler.
+func (r *RingBufferHandler) Close() {
+	r.m.Lock()
+	r.buffers = make(map[string]*entryRing)
+	r.lru.Init()
+	r.m.Unlock()
+	r.handler.Close()
+}
+
+// entryRing is a fixed size circular buffer of entries.
+type entryRing struct {
+	entries []*Entry
+	start   int
+	count   int
+	elem    *list.Element // Position in the handler's LRU list
+}
+
+func newEntryRing(size int) *entryRing {
+	return &entryRing{entries: make([]*Entry, size)}
+}
+
+func (r *entryRing) push(e *Entry) {
+	i := (r.start + r.count) % len(r.entries)
+	r.entries[i] = e
+	if r.count < len(r.entries) {
+		r.count++
+	} else {
+		r.start = (r.start + 1) % len(r.entries)
+	}
+}
+
+// drain returns the buffered entries oldest first and empties the ring.
+func (r *entryRing) drain() []*Entry {
+	out := make([]*Entry, r.count)
+	for i := range out {
+		out[i] = r.entries[(r.start+i)%len(r.entries)]
+		r.entries[(r.start+i)%len(r.entries)] = nil
+	}
+	r.start = 0
+	r.count = 0
+	return out
+}
@@ -0,0 +1,146 @@
+package verbose
+
+import (
+	"sync"
+	"testing"
+)
+
+// recordHandler keeps every entry it's given
+type recordHandler struct {
+	entries []*Entry
+	m       sync.Mutex
+}
+
+func (r *recordHandler) Handles(_ LogLevel) bool  { return true }
+func (_ *recordHandler) SetFormatter(_ Formatter) {}
+func (_ *recordHandler) Close()                   {}
+func (_ *recordHandler) SetLevel(_ LogLevel)      {}
+func (_ *recordHandler) SetMinLevel(_ LogLevel)   {}
+func (_ *recordHandler) SetMaxLevel(_ LogLevel)   {}
+
+func (r *recordHandler) WriteLog(e *Entry) {
+	r.m.Lock()
+	r.entries = append(r.entries, e.Clone())
+	r.m.Unlock()
+}
+
+func (r *recordHandler) messages() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	msgs := make([]string, len(r.entries))
+	for i, e := range r.entries {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+func checkMessages(t *testing.T, expected, got []string) {
+	t.Helper()
+	if len(expected) != len(got) {
+		t.Fatalf("Incorrect messages. Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Errorf("Incorrect messages. Expected %v, got %v", expected, got)
+			return
+		}
+	}
+}
+
+func TestRingBufferHandler(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("ring", NewRingBufferHandler(rec, 3, LogLevelError))
+
+	logger.Debug("one")
+	logger.Info("two")
+	logger.Debug("three")
+	logger.Warning("four")
+	checkMessages(t, []string{}, rec.messages())
+
+	logger.Error("five")
+	checkMessages(t, []string{"two", "three", "four", "five"}, rec.messages())
+
+	logger.Debug("six")
+	logger.Critical("seven")
+	checkMessages(t, []string{"two", "three", "four", "five", "six", "seven"}, rec.messages())
+}
+
+func TestRingBufferHandlerKeyField(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	rh := NewRingBufferHandler(rec, 10, LogLevelError)
+	rh.SetKeyField("request")
+	logger := New("logger")
+	logger.AddHandler("ring", rh)
+
+	req1 := logger.WithField("request", 1)
+	req2 := logger.WithField("request", 2)
+	req1.Debug("one")
+	req2.Debug("two")
+	req1.Info("three")
+	req2.Error("four")
+	checkMessages(t, []string{"two", "four"}, rec.messages())
+
+	rh.Clear("request=1")
+	req1.Error("five")
+	checkMessages(t, []string{"two", "four", "five"}, rec.messages())
+}
+
+func TestRingBufferHandlerFlush(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	rh := NewRingBufferHandler(rec, 2, LogLevelError)
+	logger := New("logger")
+	logger.AddHandler("ring", rh)
+
+	e := logger.WithField("reused", true)
+	e.Debug("one")
+	e.Debug("two")
+	rh.Flush()
+	checkMessages(t, []string{"one", "two"}, rec.messages())
+}
+
+func TestRingBufferHandlerMaxKeys(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	rh := NewRingBufferHandler(rec, 10, LogLevelError)
+	rh.SetKeyField("request")
+	rh.SetMaxKeys(2)
+	logger := New("logger")
+	logger.AddHandler("ring", rh)
+
+	req1 := logger.WithField("request", 1)
+	req2 := logger.WithField("request", 2)
+	req3 := logger.WithField("request", 3)
+	req1.Debug("one")
+	req2.Debug("two")
+	req1.Debug("three") // Request 2 is now the least recently used
+	req3.Debug("four")  // Evicts request 2
+
+	req2.Error("five")
+	checkMessages(t, []string{"five"}, rec.messages())
+
+	rh.Flush()
+	got := rec.messages()
+	if len(got) != 4 {
+		t.Fatalf("Incorrect number of flushed entries. Expected 4, got %v", got)
+	}
+	for _, msg := range got {
+		if msg == "two" {
+			t.Errorf("Evicted entry was flushed: %v", got)
+		}
+	}
+
+	rh.SetMaxKeys(0)
+	for i := 0; i < DefaultRingBufferMaxKeys+1; i++ {
+		logger.WithField("request", i).Debug("unlimited")
+	}
+	rh.m.Lock()
+	n := len(rh.buffers)
+	rh.m.Unlock()
+	if n != DefaultRingBufferMaxKeys+1 {
+		t.Errorf("Incorrect number of buffers. Expected %d, got %d", DefaultRingBufferMaxKeys+1, n)
+	}
+}
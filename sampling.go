@@ -0,0 +1,302 @@
+package verbose
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// SampleDroppedKey is the field holding the number of dropped entries in
+// sampling reports
+const SampleDroppedKey = "dropped"
+
+// A Sampler decides which entries are logged. Samplers are used by a Logger
+// with Logger.SetSampler or wrapped around a Handler with NewSamplingHandler.
+// The entry's Level, Message and Data are set when Sample is called.
+type Sampler interface {
+	// Sample returns if the entry should be logged.
+	Sample(*Entry) bool
+}
+
+// NewRateLimitSampler returns a token bucket Sampler allowing rate entries
+// per second per logger and level with bursts of up to burst entries.
+func NewRateLimitSampler(rate float64, burst int) Sampler {
+	return &rateLimitSampler{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimitSampler struct {
+	rate    float64
+	burst   float64
+	buckets map[string]*tokenBucket
+	m       sync.Mutex
+}
+
+func (r *rateLimitSampler) Sample(e *Entry) bool {
+	key := fmt.Sprintf("%s\x00%d", e.Logger.Name(), e.Level)
+	now := time.Now()
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: r.burst, last: now}
+		r.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * r.rate
+	if b.tokens > r.burst {
+		b.tokens = r.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// NewFirstThenEverySampler returns a Sampler that allows the first entries
+// with the same level and message each tick, then every thereafter-th entry.
+// If thereafter is 0, all entries after first are dropped until the next tick.
+func NewFirstThenEverySampler(tick time.Duration, first, thereafter int) Sampler {
+	return &firstThenEverySampler{
+		tick:       tick,
+		first:      first,
+		thereafter: thereafter,
+		counts:     make(map[string]int),
+	}
+}
+
+type firstThenEverySampler struct {
+	tick       time.Duration
+	first      int
+	thereafter int
+	counts     map[string]int
+	reset      time.Time
+	m          sync.Mutex
+}
+
+func (f *firstThenEverySampler) Sample(e *Entry) bool {
+	key := fmt.Sprintf("%d\x00%s", e.Level, e.Message)
+	now := time.Now()
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if !now.Before(f.reset) {
+		f.counts = make(map[string]int)
+		f.reset = now.Add(f.tick)
+	}
+
+	f.counts[key]++
+	n := f.counts[key]
+	if n <= f.first {
+		return true
+	}
+	return f.thereafter > 0 && (n-f.first)%f.thereafter == 0
+}
+
+// NewRandomSampler returns a Sampler that allows entries at the given levels
+// with probability p between 0 and 1. Entries at other levels are always
+// allowed. If no levels are given, Debug and Info are sampled.
+func NewRandomSampler(p float64, levels ...LogLevel) Sampler {
+	if len(levels) == 0 {
+		levels = []LogLevel{LogLevelDebug, LogLevelInfo}
+	}
+	r := &randomSampler{
+		p:      p,
+		levels: make(map[LogLevel]bool, len(levels)),
+	}
+	for _, l := range levels {
+		r.levels[l] = true
+	}
+	return r
+}
+
+type randomSampler struct {
+	p      float64
+	levels map[LogLevel]bool
+}
+
+func (r *randomSampler) Sample(e *Entry) bool {
+	if !r.levels[e.Level] {
+		return true
+	}
+	return rand.Float64() < r.p
+}
+
+// MultiSampler returns a Sampler that only allows entries allowed by all
+// the given samplers.
+func MultiSampler(samplers ...Sampler) Sampler {
+	return multiSampler(samplers)
+}
+
+type multiSampler []Sampler
+
+func (m multiSampler) Sample(e *Entry) bool {
+	for _, s := range m {
+		if !s.Sample(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// sampleCounter applies a Sampler and counts the dropped entries so they
+// can be reported. If the report interval is set, a goroutine calls flush
+// every interval until stop is called.
+type sampleCounter struct {
+	sampler  Sampler
+	interval time.Duration
+	dropped  int
+	level    LogLevel
+	logger   *Logger
+	clock    Clock
+	done     chan struct{}
+	m        sync.Mutex
+}
+
+func newSampleCounter(s Sampler, report time.Duration, flush func(*Entry)) *sampleCounter {
+	c := &sampleCounter{
+		sampler:  s,
+		interval: report,
+	}
+	if report > 0 {
+		c.done = make(chan struct{})
+		go c.run(c.done, flush)
+	}
+	return c
+}
+
+func (c *sampleCounter) run(done chan struct{}, flush func(*Entry)) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if report := c.report(); report != nil {
+				flush(report)
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// sample returns if e should be logged. The logger lock must be held.
+func (c *sampleCounter) sample(e *Entry) bool {
+	ok := c.sampler.Sample(e)
+	if ok {
+		return true
+	}
+
+	c.m.Lock()
+	if c.dropped == 0 || e.Level > c.level {
+		c.level = e.Level
+	}
+	c.dropped++
+	c.logger = e.Logger
+	c.clock = e.Logger.clock
+	c.m.Unlock()
+	return false
+}
+
+// report returns an entry reporting how many entries were dropped since the
+// last report, or nil if none were or drops aren't reported. The report uses
+// the highest level that was dropped so it reaches the handlers that would
+// have written the dropped entries.
+func (c *sampleCounter) report() *Entry {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.interval <= 0 || c.dropped == 0 {
+		return nil
+	}
+
+	report := NewEntry(c.logger)
+	report.Level = c.level
+	report.Message = fmt.Sprintf("sampling dropped %d entries", c.dropped)
+	report.Timestamp = time.Now()
+	if c.clock != nil {
+		report.Timestamp = c.clock.Now()
+	}
+	report.Data[SampleDroppedKey] = c.dropped
+	c.dropped = 0
+	return report
+}
+
+// stop stops the periodic reports and returns the final report, if any.
+func (c *sampleCounter) stop() *Entry {
+	c.m.Lock()
+	if c.done != nil {
+		close(c.done)
+		c.done = nil
+	}
+	c.m.Unlock()
+	return c.report()
+}
+
+// SamplingHandler wraps a handler and only writes the entries allowed by a
+// Sampler. The number of dropped entries is periodically written as an entry
+// and a final report is written when the handler is closed.
+type SamplingHandler struct {
+	levelRange
+	handler Handler
+	counter *sampleCounter
+}
+
+// NewSamplingHandler creates a SamplingHandler that writes the entries
+// allowed by s to h. The number of dropped entries is reported every report
+// interval if any were dropped. If report is 0, drops aren't reported.
+func NewSamplingHandler(h Handler, s Sampler, report time.Duration) *SamplingHandler {
+	sh := &SamplingHandler{
+		levelRange: newLevelRange(),
+		handler:    h,
+	}
+	sh.counter = newSampleCounter(s, report, sh.writeReport)
+	return sh
+}
+
+// SetFormatter sets the formatter of the wrapped handler.
+func (s *SamplingHandler) SetFormatter(f Formatter) {
+	s.handler.SetFormatter(f)
+}
+
+// Handles returns whether both the handler and the wrapped handler handle
+// log level l.
+func (s *SamplingHandler) Handles(l LogLevel) bool {
+	return s.levelRange.Handles(l) && s.handler.Handles(l)
+}
+
+// WriteLog writes the entry to the wrapped handler if the sampler allows it.
+func (s *SamplingHandler) WriteLog(e *Entry) {
+	if s.counter.sample(e) {
+		s.handler.WriteLog(e)
+	}
+}
+
+func (s *SamplingHandler) writeReport(report *Entry) {
+	if s.handler.Handles(report.Level) {
+		s.handler.WriteLog(report)
+	}
+}
+
+// Close writes the final report of dropped entries and closes the wrapped
+// handler.
+func (s *SamplingHandler) Close() {
+	if report := s.counter.stop(); report != nil {
+		s.writeReport(report)
+	}
+	s.handler.Close()
+}
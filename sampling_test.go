@@ -0,0 +1,159 @@
+package verbose
+
+import (
+	"testing"
+	"time"
+)
+
+func newSampleEntry(level LogLevel, msg string) *Entry {
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = level
+	e.Message = msg
+	return e
+}
+
+func TestRateLimitSampler(t *testing.T) {
+	s := NewRateLimitSampler(0.001, 3)
+
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if s.Sample(newSampleEntry(LogLevelInfo, "hello")) {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("Incorrect number of allowed entries. Expected 3, got %d", allowed)
+	}
+
+	// Each level has its own bucket
+	if !s.Sample(newSampleEntry(LogLevelError, "hello")) {
+		t.Error("Error entry should have been allowed")
+	}
+}
+
+func TestFirstThenEverySampler(t *testing.T) {
+	s := NewFirstThenEverySampler(time.Hour, 2, 3)
+
+	var allowed []int
+	for i := 1; i <= 10; i++ {
+		if s.Sample(newSampleEntry(LogLevelInfo, "hello")) {
+			allowed = append(allowed, i)
+		}
+	}
+
+	expected := []int{1, 2, 5, 8}
+	if len(allowed) != len(expected) {
+		t.Fatalf("Incorrect entries allowed. Expected %v, got %v", expected, allowed)
+	}
+	for i := range expected {
+		if allowed[i] != expected[i] {
+			t.Fatalf("Incorrect entries allowed. Expected %v, got %v", expected, allowed)
+		}
+	}
+
+	if !s.Sample(newSampleEntry(LogLevelInfo, "different")) {
+		t.Error("Different message should have been allowed")
+	}
+}
+
+func TestRandomSampler(t *testing.T) {
+	s := NewRandomSampler(0)
+	if s.Sample(newSampleEntry(LogLevelDebug, "hello")) {
+		t.Error("Debug entry should have been dropped")
+	}
+	if !s.Sample(newSampleEntry(LogLevelWarning, "hello")) {
+		t.Error("Warning entry should have been allowed")
+	}
+
+	s = NewRandomSampler(1, LogLevelWarning)
+	if !s.Sample(newSampleEntry(LogLevelWarning, "hello")) {
+		t.Error("Warning entry should have been allowed")
+	}
+}
+
+func TestSamplingHandler(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	sh := NewSamplingHandler(rec, NewFirstThenEverySampler(time.Hour, 1, 0), time.Hour)
+	logger := New("logger")
+	logger.AddHandler("sample", sh)
+
+	logger.Info("hello")
+	logger.Info("hello")
+	logger.Error("hello")
+	checkMessages(t, []string{"hello", "hello"}, rec.messages())
+
+	// A burst followed by silence is reported when the handler is closed
+	sh.Close()
+	checkMessages(t, []string{"hello", "hello", "sampling dropped 1 entries"}, rec.messages())
+
+	report := rec.entries[2]
+	if report.Level != LogLevelInfo {
+		t.Errorf("Incorrect report level. Expected %d, got %d", LogLevelInfo, report.Level)
+	}
+	if report.Data[SampleDroppedKey] != 1 {
+		t.Errorf("Incorrect dropped count. Expected 1, got %v", report.Data[SampleDroppedKey])
+	}
+}
+
+func TestSamplingReportInterval(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+	logger.SetSampler(NewFirstThenEverySampler(time.Hour, 1, 0), 10*time.Millisecond)
+	defer logger.Close()
+
+	logger.Warning("hello")
+	logger.Warning("hello")
+	logger.Warning("hello")
+
+	expected := []string{"hello", "sampling dropped 2 entries"}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(rec.messages()) < len(expected) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	checkMessages(t, expected, rec.messages())
+
+	time.Sleep(30 * time.Millisecond)
+	checkMessages(t, expected, rec.messages())
+}
+
+func TestLoggerSampler(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+	logger.SetSampler(NewFirstThenEverySampler(time.Hour, 1, 0), 0)
+
+	logger.Info("hello")
+	logger.Info("hello")
+	logger.WithField("key", "value").Info("hello")
+	checkMessages(t, []string{"hello"}, rec.messages())
+
+	logger.SetSampler(nil, 0)
+	logger.Info("hello")
+	checkMessages(t, []string{"hello", "hello"}, rec.messages())
+}
+
+func TestLoggerSamplerReportFields(t *testing.T) {
+	clearLoggers()
+	SetStaticFields(Fields{"service": "api", "token": "abc123"})
+	defer SetStaticFields(nil)
+
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+	logger.SetRedaction(&Redaction{Keys: DefaultRedactKeys})
+	logger.SetSampler(NewFirstThenEverySampler(time.Hour, 1, 0), time.Hour)
+
+	logger.Info("hello")
+	logger.Info("hello")
+	logger.SetSampler(nil, 0)
+	checkMessages(t, []string{"hello", "sampling dropped 1 entries"}, rec.messages())
+
+	report := rec.entries[1]
+	if report.Data["service"] != "api" || report.Data["token"] != RedactedValue {
+		t.Errorf("Report without static fields or redaction. Got %v", report.Data)
+	}
+}
@@ -0,0 +1,121 @@
+package verbose
+
+import (
+	"os"
+	"sync"
+)
+
+// Keys of the fields returned by ProcessInfo.Fields
+const (
+	StaticHostnameKey    = "hostname"
+	StaticPIDKey         = "pid"
+	StaticServiceKey     = "service"
+	StaticVersionKey     = "version"
+	StaticEnvironmentKey = "environment"
+	StaticGitSHAKey      = "git_sha"
+)
+
+var (
+	staticFields      Fields
+	staticFieldsMutex = sync.RWMutex{}
+)
+
+// SetStaticFields sets fields included in every entry of every logger, such
+// as the hostname or service name. Fields added to an entry with WithField
+// take precedence over static fields with the same key. It replaces any
+// previous static fields, nil removes them.
+func SetStaticFields(f Fields) {
+	c := make(Fields, len(f))
+	for k, v := range f {
+		c[k] = v
+	}
+	staticFieldsMutex.Lock()
+	staticFields = c
+	staticFieldsMutex.Unlock()
+}
+
+// StaticFields returns a copy of the static fields.
+func StaticFields() Fields {
+	staticFieldsMutex.RLock()
+	defer staticFieldsMutex.RUnlock()
+	c := make(Fields, len(staticFields))
+	for k, v := range staticFields {
+		c[k] = v
+	}
+	return c
+}
+
+// withStaticFields returns e with the static fields added. If there are no
+// static fields, e is returned as is. Otherwise e isn't changed so static
+// fields never stick to a reused entry. The static fields the entry doesn't
+// override are recorded in the returned entry for formatters treating them
+// separately.
+func (e *Entry) withStaticFields() *Entry {
+	staticFieldsMutex.RLock()
+	defer staticFieldsMutex.RUnlock()
+	if len(staticFields) == 0 {
+		return e
+	}
+
+	c := *e
+	c.static = staticFields // Replaced, never changed, by SetStaticFields
+	c.Data = make(Fields, len(staticFields)+len(e.Data))
+	for k, v := range staticFields {
+		c.Data[k] = v
+	}
+	for k, v := range e.Data {
+		c.Data[k] = v
+	}
+
+	// Only record the static fields the entry didn't override
+	for k := range e.Data {
+		if _, ok := staticFields[k]; !ok {
+			continue
+		}
+		if len(c.static) == len(staticFields) {
+			c.static = make(Fields, len(staticFields))
+			for k, v := range staticFields {
+				c.static[k] = v
+			}
+		}
+		delete(c.static, k)
+	}
+	return &c
+}
+
+// isStatic returns if field key of e was taken from the static fields and
+// not overridden by the entry.
+func (e *Entry) isStatic(key string) bool {
+	_, ok := e.static[key]
+	return ok
+}
+
+// ProcessInfo describes the running service for static fields.
+//
+//	verbose.SetStaticFields(verbose.ProcessInfo{Service: "api", Version: "1.4.2"}.Fields())
+type ProcessInfo struct {
+	Service     string
+	Version     string
+	Environment string
+	GitSHA      string
+}
+
+// Fields returns the hostname and PID of the process along with the non-empty
+// fields of p.
+func (p ProcessInfo) Fields() Fields {
+	f := Fields{StaticPIDKey: os.Getpid()}
+	if host, err := os.Hostname(); err == nil {
+		f[StaticHostnameKey] = host
+	}
+	for k, v := range map[string]string{
+		StaticServiceKey:     p.Service,
+		StaticVersionKey:     p.Version,
+		StaticEnvironmentKey: p.Environment,
+		StaticGitSHAKey:      p.GitSHA,
+	} {
+		if v != "" {
+			f[k] = v
+		}
+	}
+	return f
+}
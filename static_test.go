@@ -0,0 +1,136 @@
+package verbose
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStaticFields(t *testing.T) {
+	clearLoggers()
+	defer SetStaticFields(nil)
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+
+	SetStaticFields(Fields{"service": "api", "version": "1.0"})
+	e := logger.WithField("version", "override")
+	e.Info("one")
+	SetStaticFields(Fields{"service": "worker"})
+	e.Info("two")
+	SetStaticFields(nil)
+	e.Info("three")
+
+	expected := []Fields{
+		{"service": "api", "version": "override"},
+		{"service": "worker", "version": "override"},
+		{"version": "override"},
+	}
+	for i, fields := range expected {
+		data := rec.entries[i].Data
+		if len(data) != len(fields) {
+			t.Errorf("Incorrect fields for entry %d. Expected %v, got %v", i, fields, data)
+			continue
+		}
+		for k, v := range fields {
+			if data[k] != v {
+				t.Errorf("Incorrect fields for entry %d. Expected %v, got %v", i, fields, data)
+			}
+		}
+	}
+	if len(e.Data) != 1 {
+		t.Errorf("Static fields were added to the reused entry. Got %v", e.Data)
+	}
+}
+
+func TestStaticFieldsCopy(t *testing.T) {
+	defer SetStaticFields(nil)
+	fields := Fields{"service": "api"}
+	SetStaticFields(fields)
+	fields["service"] = "changed"
+	StaticFields()["service"] = "changed"
+	if StaticFields()["service"] != "api" {
+		t.Error("Static fields changed through a shared map")
+	}
+}
+
+func TestProcessInfoFields(t *testing.T) {
+	f := ProcessInfo{Service: "api", GitSHA: "abc123"}.Fields()
+	host, _ := os.Hostname()
+	if f[StaticPIDKey] != os.Getpid() || f[StaticHostnameKey] != host {
+		t.Errorf("Incorrect process fields. Got %v", f)
+	}
+	if f[StaticServiceKey] != "api" || f[StaticGitSHAKey] != "abc123" {
+		t.Errorf("Incorrect service fields. Got %v", f)
+	}
+	if _, ok := f[StaticVersionKey]; ok {
+		t.Error("Empty version shouldn't be included")
+	}
+}
+
+func TestJSONFormatterStaticTopLevel(t *testing.T) {
+	defer SetStaticFields(nil)
+	SetStaticFields(Fields{"service": "api", "message": "static"})
+
+	e := NewEntry(&Logger{name: "logger"}).WithField("id", 1).withStaticFields()
+	e.Level = LogLevelInfo
+	e.Message = "Hello"
+	e.Timestamp = time.Now()
+
+	// The entry keeps the static fields it was given
+	SetStaticFields(Fields{"id": 2})
+
+	formatter := NewJSONFormatter()
+	formatter.SetStaticFieldsTopLevel(true)
+	var out map[string]interface{}
+	if err := json.Unmarshal(formatter.FormatByte(e), &out); err != nil {
+		t.Fatalf("Invalid JSON: %s", err.Error())
+	}
+	if out["service"] != "api" || out["message"] != "Hello" {
+		t.Errorf("Static field not at top level. Got %v", out)
+	}
+	data := out["data"].(map[string]interface{})
+	if len(data) != 2 || data["id"] != "1" || data["message"] != "static" {
+		t.Errorf("Incorrect data. Got %v", data)
+	}
+}
+
+func TestJSONFormatterStaticOverride(t *testing.T) {
+	defer SetStaticFields(nil)
+	SetStaticFields(Fields{"service": "api", "region": "eu"})
+
+	e := NewEntry(&Logger{name: "logger"}).WithField("service", "worker").withStaticFields()
+	e.Level = LogLevelInfo
+	e.Message = "Hello"
+	e.Timestamp = time.Now()
+
+	formatter := NewJSONFormatter()
+	formatter.SetStaticFieldsTopLevel(true)
+	var out map[string]interface{}
+	if err := json.Unmarshal(formatter.FormatByte(e), &out); err != nil {
+		t.Fatalf("Invalid JSON: %s", err.Error())
+	}
+	if _, ok := out["service"]; ok || out["region"] != "eu" {
+		t.Errorf("Incorrect top level fields. Got %v", out)
+	}
+	data := out["data"].(map[string]interface{})
+	if len(data) != 1 || data["service"] != "worker" {
+		t.Errorf("Overridden static field not in data. Got %v", data)
+	}
+	if StaticFields()["service"] != "api" {
+		t.Errorf("Static fields were changed. Got %v", StaticFields())
+	}
+}
+
+func TestConfigureStaticFields(t *testing.T) {
+	clearLoggers()
+	defer SetStaticFields(nil)
+	err := Configure([]byte(`{"loggers": {}, "static_fields": {"service": "api"}}`))
+	if err != nil {
+		t.Fatalf("Error configuring: %s", err.Error())
+	}
+	if StaticFields()["service"] != "api" {
+		t.Errorf("Static fields not set. Got %v", StaticFields())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 )
 
 // Color is an escaped color code for the terminal
@@ -23,6 +24,7 @@ const (
 )
 
 var colors = map[LogLevel]Color{
+	LogLevelTrace:     ColorWhite,
 	LogLevelDebug:     ColorBlue,
 	LogLevelInfo:      ColorCyan,
 	LogLevelNotice:    ColorCyan,
@@ -35,16 +37,21 @@ var colors = map[LogLevel]Color{
 }
 
 // StdoutHandler writes log message to standard out
-// It even uses color!
+// It even uses color! Higher levels can be written to standard error
+// instead with SetStderrLevel.
 type StdoutHandler struct {
-	min       LogLevel
-	max       LogLevel
-	out       io.Writer // Usually os.Stdout, mainly used for testing
-	formatter Formatter
+	levelRange
+	formatterHolder
+	out      io.Writer // Usually os.Stdout, mainly used for testing
+	err      io.Writer // Usually os.Stderr, mainly used for testing
+	errLevel LogLevel
+	split    bool
+	m        sync.Mutex
 }
 
 // NewStdoutHandler creates a new StdoutHandler, surprise!
 // Color specifies if the log messages will be printed to a colored terminal.
+// Use NewStdoutHandlerWithColor to only use color when stdout is a terminal.
 func NewStdoutHandler(color bool) *StdoutHandler {
 	var formatter Formatter
 	if color {
@@ -53,49 +60,52 @@ func NewStdoutHandler(color bool) *StdoutHandler {
 		formatter = NewLineFormatter()
 	}
 	return &StdoutHandler{
-		min:       LogLevelDebug,
-		max:       LogLevelFatal,
-		out:       os.Stdout,
-		formatter: formatter,
+		levelRange:      newLevelRange(),
+		formatterHolder: formatterHolder{formatter: formatter},
+		out:             os.Stdout,
+		err:             os.Stderr,
 	}
 }
 
-// SetLevel will set both the minimum and maximum log levels to l. This makes
-// the handler only respond to the single level l.
-func (s *StdoutHandler) SetLevel(l LogLevel) {
-	s.min = l
-	s.max = l
+// NewStdoutHandlerWithColor creates a new StdoutHandler using color
+// according to mode m. With ColorModeAuto, color is used if stdout is a
+// terminal and the NO_COLOR and FORCE_COLOR environment variables allow it.
+func NewStdoutHandlerWithColor(m ColorMode) *StdoutHandler {
+	return NewStdoutHandler(UseColor(m, os.Stdout))
 }
 
-// SetMinLevel will set the minimum log level the handler will handle.
-func (s *StdoutHandler) SetMinLevel(l LogLevel) {
-	if l > s.max {
-		return
-	}
-	s.min = l
+// SetStderrLevel makes the handler write entries at level l and above to
+// standard error. Lower levels are still written to standard out.
+func (s *StdoutHandler) SetStderrLevel(l LogLevel) {
+	s.m.Lock()
+	s.errLevel = l
+	s.split = true
+	s.m.Unlock()
 }
 
-// SetMaxLevel will set the maximum log level the handler will handle.
-func (s *StdoutHandler) SetMaxLevel(l LogLevel) {
-	if l < s.min {
-		return
-	}
-	s.max = l
+// DisableStderr makes the handler write all entries to standard out. This
+// is the default.
+func (s *StdoutHandler) DisableStderr() {
+	s.m.Lock()
+	s.split = false
+	s.m.Unlock()
 }
 
-// SetFormatter gives StdoutHandler a formatter for log messages.
-func (s *StdoutHandler) SetFormatter(f Formatter) {
-	s.formatter = f
-}
-
-// Handles returns whether the handler handles log level l.
-func (s *StdoutHandler) Handles(l LogLevel) bool {
-	return (s.min <= l && l <= s.max)
-}
-
-// WriteLog writes the log message to standard output
+// WriteLog writes the log message to standard output, or standard error if
+// the level is at or above the stderr level. Writes are serialized so
+// concurrent entries never interleave.
 func (s *StdoutHandler) WriteLog(e *Entry) {
-	fmt.Fprint(s.out, s.formatter.Format(e))
+	msg := s.getFormatter().FormatByte(e)
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	out := s.out
+	if s.split && e.Level >= s.errLevel {
+		out = s.err
+	}
+	if _, err := out.Write(msg); err != nil {
+		fmt.Printf("Error writing log: %v\n", err)
+	}
 }
 
 // Close satisfies the interface, NOOP
@@ -1,7 +1,11 @@
 package verbose
 
 import (
+	"bytes"
+	"io/ioutil"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -16,6 +20,40 @@ func TestStdoutDefaults(t *testing.T) {
 	if sh.out != os.Stdout {
 		t.Error("Incorrect default writer, not Stdout")
 	}
+	if sh.err != os.Stderr {
+		t.Error("Incorrect default error writer, not Stderr")
+	}
+	if sh.split {
+		t.Error("Handler shouldn't write to Stderr by default")
+	}
+}
+
+func TestStdoutStderrSplit(t *testing.T) {
+	clearLoggers()
+	sh := NewStdoutHandler(false)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	sh.out, sh.err = stdout, stderr
+	sh.SetStderrLevel(LogLevelWarning)
+	logger := New("logger")
+	logger.AddHandler("stdout", sh)
+
+	logger.Info("info")
+	logger.Warning("warning")
+	logger.Error("error")
+	if strings.Count(stdout.String(), "\n") != 1 || !strings.Contains(stdout.String(), "info") {
+		t.Errorf("Incorrect stdout. Got %q", stdout.String())
+	}
+	if strings.Count(stderr.String(), "\n") != 2 || strings.Contains(stderr.String(), "info") {
+		t.Errorf("Incorrect stderr. Got %q", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	sh.DisableStderr()
+	logger.Error("error")
+	if stderr.Len() != 0 || !strings.Contains(stdout.String(), "error") {
+		t.Errorf("Stderr not disabled. Got stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
 }
 
 func TestStdoutLevelSetting(t *testing.T) {
@@ -49,3 +87,45 @@ func TestStdoutLevelSetting(t *testing.T) {
 		t.Errorf("Incorrect Handles result. Expected true, got %t", sh.Handles(LogLevelCritical))
 	}
 }
+
+// TestStdoutConcurrentSettings changes the levels and formatter, directly and
+// from the environment, while logging.
+// It's meant to be run with the race detector.
+func TestStdoutConcurrentSettings(t *testing.T) {
+	clearLoggers()
+	setEnv(t, map[string]string{EnvFormat: "line"})
+	sh := NewStdoutHandler(false)
+	sh.out = ioutil.Discard
+	logger := New("logger")
+	logger.AddHandler("stdout", sh)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			logger.WithField("i", i).Warning("Concurrent")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			sh.SetLevel(LogLevelWarning)
+			sh.SetMinLevel(LogLevelDebug)
+			sh.SetMaxLevel(LogLevelError)
+			sh.SetFormatter(NewJSONFormatter())
+			sh.SetFormatter(NewLineFormatter())
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			ConfigureFromEnv()
+		}
+	}()
+	wg.Wait()
+
+	if sh.MinLevel() != LogLevelDebug || sh.MaxLevel() != LogLevelError {
+		t.Errorf("Incorrect levels. Expected %d-%d, got %d-%d", LogLevelDebug, LogLevelError, sh.MinLevel(), sh.MaxLevel())
+	}
+}
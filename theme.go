@@ -0,0 +1,64 @@
+package verbose
+
+import "fmt"
+
+// Text attributes. They can be combined with a color using Bold and
+// Underline.
+const (
+	ColorBold      Color = "\033[1m"
+	ColorUnderline Color = "\033[4m"
+)
+
+// Color256 returns color n of the 256 color palette.
+func Color256(n uint8) Color {
+	return Color(fmt.Sprintf("\033[38;5;%dm", n))
+}
+
+// ColorRGB returns a 24-bit truecolor color. Not all terminals support it.
+func ColorRGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b))
+}
+
+// Bold returns c in bold.
+func Bold(c Color) Color {
+	return ColorBold + c
+}
+
+// Underline returns c underlined.
+func Underline(c Color) Color {
+	return ColorUnderline + c
+}
+
+// A Theme holds the colors used by the ColoredLineFormatter. An empty color
+// leaves that part of the line uncolored. Levels missing from Levels use the
+// default level colors.
+type Theme struct {
+	Levels     map[LogLevel]Color
+	Timestamp  Color
+	Logger     Color
+	Message    Color
+	FieldKey   Color
+	FieldValue Color
+}
+
+// DefaultTheme returns the theme used by a new ColoredLineFormatter. The
+// returned theme can be changed without affecting other formatters.
+func DefaultTheme() Theme {
+	levels := make(map[LogLevel]Color, len(colors))
+	for l, c := range colors {
+		levels[l] = c
+	}
+	return Theme{
+		Levels:    levels,
+		Timestamp: ColorGrey,
+		Logger:    ColorGreen,
+	}
+}
+
+// levelColor returns the theme's color for level l.
+func (t Theme) levelColor(l LogLevel) Color {
+	if c, ok := t.Levels[l]; ok {
+		return c
+	}
+	return levelColor(l)
+}
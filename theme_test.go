@@ -0,0 +1,66 @@
+package verbose
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestColorHelpers(t *testing.T) {
+	tests := map[Color]Color{
+		Color256(208):         "\033[38;5;208m",
+		ColorRGB(255, 136, 0): "\033[38;2;255;136;0m",
+		Bold(ColorRed):        "\033[1m\033[31m",
+		Underline(ColorBlue):  "\033[4m\033[34m",
+	}
+	for c, expected := range tests {
+		if c != expected {
+			t.Errorf("Incorrect color. Expected %q, got %q", expected, c)
+		}
+	}
+}
+
+func TestColoredLineFormatterTheme(t *testing.T) {
+	now := time.Now()
+	e := NewEntry(&Logger{name: "logger"})
+	e.Level = LogLevelError
+	e.Message = "Houston, we have a problem"
+	e.Timestamp = now
+	e.Data = Fields{"key1": "value1"}
+
+	theme := DefaultTheme()
+	theme.Levels[LogLevelError] = Bold(Color256(196))
+	theme.Timestamp = ColorRGB(128, 128, 128)
+	theme.Logger = Underline(ColorBlue)
+	theme.Message = ColorWhite
+	theme.FieldKey = ColorCyan
+	theme.FieldValue = ColorYellow
+
+	formatter := NewColoredLineFormatter()
+	formatter.SetTheme(theme)
+
+	expected := fmt.Sprintf(
+		"%s%s%s: %sERROR%s: %slogger%s: %s%s%s | %s\"key1\"%s: %s\"value1\"%s\n",
+		theme.Timestamp,
+		now.Format(time.RFC3339),
+		ColorReset,
+		theme.Levels[LogLevelError],
+		ColorReset,
+		theme.Logger,
+		ColorReset,
+		ColorWhite,
+		e.Message,
+		ColorReset,
+		ColorCyan,
+		ColorReset,
+		ColorYellow,
+		ColorReset,
+	)
+	if result := formatter.Format(e); result != expected {
+		t.Errorf("Incorrectly formatted message. Expected %q, got %q", expected, result)
+	}
+
+	if DefaultTheme().Levels[LogLevelError] != ColorRed {
+		t.Error("Changing a theme changed the default theme")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"text/template"
 )
 
@@ -23,15 +24,50 @@ var levels = [...]string{
 	"Fatal",
 }
 
+// Trace is below Debug and only has a LogLevel constant. Logger.Trace is
+// used for function tracing so it doesn't get the usual level methods.
+const traceLevel = "Trace"
+
+// Alternate names accepted by ParseLevel
+var aliases = [...][2]string{
+	{"warn", "Warning"},
+	{"err", "Error"},
+	{"crit", "Critical"},
+	{"emerg", "Emergency"},
+}
+
+type templateData struct {
+	Trace   string
+	Levels  []string
+	Aliases [][2]string
+}
+
 var fileTmpl = `// This file was generated with level_generator. DO NOT EDIT
 
 package verbose
 
-import "os"
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
 
 // LogLevel is used to compare levels in a consistant manner
 type LogLevel int
 
+// Won't compile if LogLevel doesn't satisfy these interfaces
+var (
+	_ flag.Value               = new(LogLevel)
+	_ encoding.TextMarshaler   = LogLevelDebug
+	_ encoding.TextUnmarshaler = new(LogLevel)
+	_ json.Marshaler           = LogLevelDebug
+	_ json.Unmarshaler         = new(LogLevel)
+)
+
 // String returns the stringified version of LogLevel.
 // I.e., "Error" for LogLevelError, and "Debug" for LogLevelDebug
 // It will return an empty string for any undefined level.
@@ -39,19 +75,95 @@ func (l LogLevel) String() string {
 	if s, ok := levelString[l]; ok {
 		return s
 	}
-	return ""
+	return customLevelName(l)
 }
 
 // These are the defined log levels
-const ({{range $i, $l := .}}
-	LogLevel{{$l}}{{if eq $i 0}} LogLevel = iota{{end}}{{end}}
+const (
+	LogLevel{{.Trace}} LogLevel = iota - 1{{range .Levels}}
+	LogLevel{{.}}{{end}}
 )
 
 // LogLevel to stringified versions
-var levelString = map[LogLevel]string{ {{range .}}
+var levelString = map[LogLevel]string{
+	LogLevel{{.Trace}}:     "{{.Trace}}",{{range .Levels}}
 	LogLevel{{.}}:     "{{.}}",{{end}}
 }
-{{range .}}
+
+// Lowercase names and aliases to LogLevel
+var levelNames = map[string]LogLevel{
+	"{{lower .Trace}}":     LogLevel{{.Trace}},{{range .Levels}}
+	"{{lower .}}":     LogLevel{{.}},{{end}}{{range .Aliases}}
+	"{{index . 0}}":     LogLevel{{index . 1}},{{end}}
+}
+
+// ParseLevel returns the LogLevel named s. Names are case insensitive and the
+// abbreviations{{range $i, $a := .Aliases}}{{if $i}},{{end}} "{{index $a 0}}"{{end}} are accepted.
+// The number of a level is also accepted. Custom levels are recognized once
+// they're registered.
+func ParseLevel(s string) (LogLevel, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if l, ok := levelNames[name]; ok {
+		return l, nil
+	}
+	if l, ok := lookupCustomLevel(name); ok {
+		return l, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil {
+		if LogLevel(n).String() != "" {
+			return LogLevel(n), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid log level %q", s)
+}
+
+// MarshalText returns the name of the level.
+func (l LogLevel) MarshalText() ([]byte, error) {
+	s := l.String()
+	if s == "" {
+		return nil, fmt.Errorf("invalid log level %d", int(l))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText sets the level using ParseLevel.
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalJSON returns the name of the level as a JSON string.
+func (l LogLevel) MarshalJSON() ([]byte, error) {
+	text, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON sets the level from a JSON string or number using ParseLevel.
+func (l *LogLevel) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("invalid log level %s", data)
+		}
+		s = strconv.Itoa(n)
+	}
+	return l.UnmarshalText([]byte(s))
+}
+
+// Set sets the level using ParseLevel. Along with String, this makes LogLevel
+// a flag.Value.
+func (l *LogLevel) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+{{range .Levels}}
 // {{.}} - Log {{.}} message
 func (l *Logger) {{.}}(v ...interface{}) {
     NewEntry(l).{{.}}(v...){{if eq . "Fatal"}}
@@ -72,7 +184,7 @@ func (l *Logger) Print(v ...interface{}) {
 }
 
 // Printf friendly functions
-{{range .}}
+{{range .Levels}}
 // {{.}}f - Log formatted {{.}} message
 func (l *Logger) {{.}}f(m string, v ...interface{}) {
 	NewEntry(l).{{.}}f(m, v...){{if eq . "Fatal"}}
@@ -93,7 +205,7 @@ func (l *Logger) Printf(m string, v ...interface{}) {
 }
 
 // Println friendly functions
-{{range .}}
+{{range .Levels}}
 // {{.}}ln - Log {{.}} message with newline
 func (l *Logger) {{.}}ln(v ...interface{}) {
     NewEntry(l).{{.}}ln(v...){{if eq . "Fatal"}}
@@ -121,7 +233,9 @@ func init() {
 func main() {
 	flag.Parse()
 
-	tmpl, err := template.New("funcs").Parse(fileTmpl)
+	tmpl, err := template.New("funcs").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+	}).Parse(fileTmpl)
 	if err != nil {
 		fmt.Print(err)
 		os.Exit(1)
@@ -133,5 +247,9 @@ func main() {
 		os.Exit(1)
 	}
 	defer file.Close()
-	tmpl.Execute(file, levels)
+	tmpl.Execute(file, templateData{
+		Trace:   traceLevel,
+		Levels:  levels[:],
+		Aliases: aliases[:],
+	})
 }
@@ -0,0 +1,103 @@
+package verbose
+
+import (
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Fields added to entries by function tracing
+const (
+	TraceFuncKey     = "func"
+	TraceDurationKey = "duration"
+)
+
+// A Tracer logs the exit of a function traced with Logger.Trace or
+// Entry.Trace.
+type Tracer struct {
+	entry *Entry
+	name  string
+	start time.Time
+}
+
+// Trace logs entering the function name at the Trace level and returns a
+// Tracer to log its exit. If name is "", the calling function's name is used.
+//
+//	defer logger.Trace("doWork").Exit()
+func (l *Logger) Trace(name string) *Tracer {
+	return NewEntry(l).trace(name)
+}
+
+// Trace logs entering the function name at the Trace level and returns a
+// Tracer to log its exit. If name is "", the calling function's name is used.
+func (e *Entry) Trace(name string) *Tracer {
+	return e.trace(name)
+}
+
+func (e *Entry) trace(name string) *Tracer {
+	if name == "" {
+		name = callerName(3)
+	}
+	entry := e.WithField(TraceFuncKey, name)
+	entry.Logf(LogLevelTrace, "enter %s", name)
+	return &Tracer{
+		entry: entry,
+		name:  name,
+		start: e.Logger.Now(),
+	}
+}
+
+// Exit logs leaving the traced function at the Trace level with the time
+// since it was entered.
+func (t *Tracer) Exit() {
+	d := t.entry.Logger.Now().Sub(t.start)
+	t.entry.WithField(TraceDurationKey, d).Logf(LogLevelTrace, "exit %s after %s", t.name, d)
+}
+
+// Timed returns a function that logs how long it's been since Timed was
+// called at the Trace level. If name is "", the calling function's name is
+// used.
+//
+//	defer logger.Timed("query")()
+func (l *Logger) Timed(name string) func() {
+	return NewEntry(l).timed(name)
+}
+
+// Timed returns a function that logs how long it's been since Timed was
+// called at the Trace level. If name is "", the calling function's name is
+// used.
+func (e *Entry) Timed(name string) func() {
+	return e.timed(name)
+}
+
+func (e *Entry) timed(name string) func() {
+	if name == "" {
+		name = callerName(3)
+	}
+	start := e.Logger.Now()
+	return func() {
+		d := e.Logger.Now().Sub(start)
+		e.WithFields(Fields{
+			TraceFuncKey:     name,
+			TraceDurationKey: d,
+		}).Logf(LogLevelTrace, "%s took %s", name, d)
+	}
+}
+
+// callerName returns the name of the function skip frames up the stack
+// without its package path.
+func callerName(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	if i := strings.LastIndexByte(name, '/'); i > -1 {
+		name = name[i+1:]
+	}
+	return name
+}
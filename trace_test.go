@@ -0,0 +1,80 @@
+package verbose
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func doTracedWork(logger *Logger) {
+	defer logger.Trace("").Exit()
+}
+
+func TestTrace(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+
+	func() {
+		defer logger.WithField("id", 42).Trace("doWork").Exit()
+		time.Sleep(time.Millisecond)
+	}()
+
+	if len(rec.entries) != 2 {
+		t.Fatalf("Incorrect number of entries. Expected 2, got %d", len(rec.entries))
+	}
+	enter, exit := rec.entries[0], rec.entries[1]
+	if enter.Level != LogLevelTrace || exit.Level != LogLevelTrace {
+		t.Errorf("Incorrect levels. Expected %d, got %d and %d", LogLevelTrace, enter.Level, exit.Level)
+	}
+	if enter.Message != "enter doWork" {
+		t.Errorf("Incorrect enter message. Expected `enter doWork`, got `%s`", enter.Message)
+	}
+	if !strings.HasPrefix(exit.Message, "exit doWork after ") {
+		t.Errorf("Incorrect exit message. Got `%s`", exit.Message)
+	}
+	if exit.Data[TraceFuncKey] != "doWork" || exit.Data["id"] != 42 {
+		t.Errorf("Incorrect exit fields. Got %v", exit.Data)
+	}
+	if d, ok := exit.Data[TraceDurationKey].(time.Duration); !ok || d < time.Millisecond {
+		t.Errorf("Incorrect duration. Got %v", exit.Data[TraceDurationKey])
+	}
+	if _, ok := enter.Data[TraceDurationKey]; ok {
+		t.Error("Enter entry shouldn't have a duration")
+	}
+
+	rec.entries = nil
+	doTracedWork(logger)
+	if len(rec.entries) != 2 || !strings.HasSuffix(rec.entries[0].Message, ".doTracedWork") {
+		t.Errorf("Caller name not used. Got %v", rec.messages())
+	}
+}
+
+func TestTimed(t *testing.T) {
+	clearLoggers()
+	rec := &recordHandler{}
+	logger := New("logger")
+	logger.AddHandler("rec", rec)
+
+	func() {
+		defer logger.Timed("query")()
+	}()
+
+	if len(rec.entries) != 1 {
+		t.Fatalf("Incorrect number of entries. Expected 1, got %d", len(rec.entries))
+	}
+	e := rec.entries[0]
+	if e.Level != LogLevelTrace {
+		t.Errorf("Incorrect level. Expected %d, got %d", LogLevelTrace, e.Level)
+	}
+	if !strings.HasPrefix(e.Message, "query took ") {
+		t.Errorf("Incorrect message. Got `%s`", e.Message)
+	}
+	if e.Data[TraceFuncKey] != "query" {
+		t.Errorf("Incorrect func field. Expected query, got %v", e.Data[TraceFuncKey])
+	}
+	if _, ok := e.Data[TraceDurationKey].(time.Duration); !ok {
+		t.Errorf("Incorrect duration. Got %v", e.Data[TraceDurationKey])
+	}
+}
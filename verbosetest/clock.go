@@ -0,0 +1,45 @@
+package verbosetest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lfkeitel/verbose/v4"
+)
+
+// Won't compile if FakeClock doesn't satisfy verbose.Clock
+var _ verbose.Clock = &FakeClock{}
+
+// FakeClock is a verbose.Clock that only changes when told to. Set it on a
+// logger with Logger.SetClock to get exact entry timestamps. It's safe for
+// concurrent use.
+type FakeClock struct {
+	now time.Time
+	m   sync.Mutex
+}
+
+// NewFakeClock creates a FakeClock set to t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+// Set sets the clock's current time to t.
+func (c *FakeClock) Set(t time.Time) {
+	c.m.Lock()
+	c.now = t
+	c.m.Unlock()
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.m.Lock()
+	c.now = c.now.Add(d)
+	c.m.Unlock()
+}
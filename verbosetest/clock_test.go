@@ -0,0 +1,38 @@
+package verbosetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lfkeitel/verbose/v4"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2017, time.March, 4, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	logger, h := NewLogger("verbosetest")
+	defer logger.Close()
+	logger.SetClock(clock)
+
+	logger.Info("one")
+	clock.Advance(time.Minute)
+	logger.Info("two")
+	clock.Set(start)
+	logger.Info("three")
+
+	expected := []time.Time{start, start.Add(time.Minute), start}
+	entries := h.Entries()
+	if len(entries) != len(expected) {
+		t.Fatalf("Incorrect number of entries. Expected %d, got %d", len(expected), len(entries))
+	}
+	for i, e := range entries {
+		if !e.Timestamp.Equal(expected[i]) {
+			t.Errorf("Incorrect timestamp for %q. Expected %s, got %s", e.Message, expected[i], e.Timestamp)
+		}
+	}
+
+	done := logger.Timed("work")
+	clock.Advance(3 * time.Second)
+	done()
+	h.AssertLogged(t, Level(verbose.LogLevelTrace), Message("work took 3s"))
+}
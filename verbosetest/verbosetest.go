@@ -0,0 +1,291 @@
+// Package verbosetest provides utilities for testing code that logs with
+// verbose.
+package verbosetest
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lfkeitel/verbose/v4"
+)
+
+// A Matcher reports whether an entry matches some condition.
+type Matcher func(*verbose.Entry) bool
+
+// Level matches entries logged at level l.
+func Level(l verbose.LogLevel) Matcher {
+	return func(e *verbose.Entry) bool { return e.Level == l }
+}
+
+// Logger matches entries logged by the logger named name.
+func Logger(name string) Matcher {
+	return func(e *verbose.Entry) bool { return e.Logger != nil && e.Logger.Name() == name }
+}
+
+// Message matches entries with the message m.
+func Message(m string) Matcher {
+	return func(e *verbose.Entry) bool { return e.Message == m }
+}
+
+// MessageContains matches entries whose message contains s.
+func MessageContains(s string) Matcher {
+	return func(e *verbose.Entry) bool { return strings.Contains(e.Message, s) }
+}
+
+// HasField matches entries with the field key.
+func HasField(key string) Matcher {
+	return func(e *verbose.Entry) bool {
+		_, ok := e.Data[key]
+		return ok
+	}
+}
+
+// Field matches entries with the field key equal to value.
+func Field(key string, value interface{}) Matcher {
+	return func(e *verbose.Entry) bool {
+		v, ok := e.Data[key]
+		return ok && reflect.DeepEqual(v, value)
+	}
+}
+
+func matchAll(e *verbose.Entry, m []Matcher) bool {
+	for _, match := range m {
+		if !match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Won't compile if Handler doesn't satisfy these interfaces
+var (
+	_ verbose.Handler          = &Handler{}
+	_ verbose.LevelReporter    = &Handler{}
+	_ verbose.LevelRangeSetter = &Handler{}
+)
+
+// Handler records every entry it's given in memory. By default it handles
+// all levels, including Trace and custom levels. It's safe for concurrent use.
+type Handler struct {
+	entries []*verbose.Entry
+	min     verbose.LogLevel
+	max     verbose.LogLevel
+	written chan struct{} // Closed and replaced after every entry
+	resets  int           // Incremented by Reset
+	m       sync.Mutex
+}
+
+// NewHandler creates a new, empty Handler.
+func NewHandler() *Handler {
+	return &Handler{
+		min:     math.MinInt32,
+		max:     math.MaxInt32,
+		written: make(chan struct{}),
+	}
+}
+
+// NewLogger creates a logger named n with a Handler named "test" and returns
+// both.
+func NewLogger(n string) (*verbose.Logger, *Handler) {
+	h := NewHandler()
+	l := verbose.New(n)
+	l.AddHandler("test", h)
+	return l, h
+}
+
+// Handles returns whether the handler handles log level l.
+func (h *Handler) Handles(l verbose.LogLevel) bool {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.min <= l && l <= h.max
+}
+
+// WriteLog records a copy of the entry.
+func (h *Handler) WriteLog(e *verbose.Entry) {
+	h.m.Lock()
+	h.entries = append(h.entries, e.Clone())
+	close(h.written)
+	h.written = make(chan struct{})
+	h.m.Unlock()
+}
+
+// SetFormatter satisfies the interface, NOOP. Entries are recorded
+// unformatted.
+func (h *Handler) SetFormatter(f verbose.Formatter) {}
+
+// Close satisfies the interface, NOOP. Recorded entries are kept.
+func (h *Handler) Close() {}
+
+// SetLevel will set both the minimum and maximum log levels to l.
+func (h *Handler) SetLevel(l verbose.LogLevel) {
+	h.m.Lock()
+	h.min = l
+	h.max = l
+	h.m.Unlock()
+}
+
+// SetMinLevel will set the minimum log level the handler will handle.
+func (h *Handler) SetMinLevel(l verbose.LogLevel) {
+	h.m.Lock()
+	if l <= h.max {
+		h.min = l
+	}
+	h.m.Unlock()
+}
+
+// SetMaxLevel will set the maximum log level the handler will handle.
+func (h *Handler) SetMaxLevel(l verbose.LogLevel) {
+	h.m.Lock()
+	if l >= h.min {
+		h.max = l
+	}
+	h.m.Unlock()
+}
+
+// SetLevelRange sets both the minimum and maximum log levels. Nothing is
+// changed if min is above max.
+func (h *Handler) SetLevelRange(min, max verbose.LogLevel) {
+	h.m.Lock()
+	if min <= max {
+		h.min = min
+		h.max = max
+	}
+	h.m.Unlock()
+}
+
+// MinLevel returns the minimum log level the handler will handle.
+func (h *Handler) MinLevel() verbose.LogLevel {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.min
+}
+
+// MaxLevel returns the maximum log level the handler will handle.
+func (h *Handler) MaxLevel() verbose.LogLevel {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.max
+}
+
+// Entries returns all recorded entries in the order they were written.
+func (h *Handler) Entries() []*verbose.Entry {
+	h.m.Lock()
+	defer h.m.Unlock()
+	entries := make([]*verbose.Entry, len(h.entries))
+	copy(entries, h.entries)
+	return entries
+}
+
+// Messages returns the messages of all recorded entries.
+func (h *Handler) Messages() []string {
+	h.m.Lock()
+	defer h.m.Unlock()
+	msgs := make([]string, len(h.entries))
+	for i, e := range h.entries {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+// Find returns the recorded entries matching all of m.
+func (h *Handler) Find(m ...Matcher) []*verbose.Entry {
+	h.m.Lock()
+	defer h.m.Unlock()
+	var found []*verbose.Entry
+	for _, e := range h.entries {
+		if matchAll(e, m) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// First returns the first recorded entry matching all of m or nil.
+func (h *Handler) First(m ...Matcher) *verbose.Entry {
+	h.m.Lock()
+	defer h.m.Unlock()
+	for _, e := range h.entries {
+		if matchAll(e, m) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Count returns the number of recorded entries matching all of m.
+func (h *Handler) Count(m ...Matcher) int {
+	return len(h.Find(m...))
+}
+
+// WaitFor waits up to timeout for an entry matching all of m to be recorded.
+// Entries recorded before WaitFor was called are also checked. It returns
+// the first matching entry or nil if the timeout expired.
+func (h *Handler) WaitFor(timeout time.Duration, m ...Matcher) *verbose.Entry {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	checked := 0
+	resets := 0 // Value of h.resets when checked was counted
+	for {
+		h.m.Lock()
+		if resets != h.resets { // Reset while waiting
+			checked = 0
+			resets = h.resets
+		}
+		for _, e := range h.entries[checked:] {
+			if matchAll(e, m) {
+				h.m.Unlock()
+				return e
+			}
+		}
+		checked = len(h.entries)
+		written := h.written
+		h.m.Unlock()
+
+		select {
+		case <-written:
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+// Reset removes all recorded entries.
+func (h *Handler) Reset() {
+	h.m.Lock()
+	h.entries = nil
+	h.resets++
+	h.m.Unlock()
+}
+
+// AssertLogged fails the test if no recorded entry matches all of m. The
+// first matching entry is returned.
+func (h *Handler) AssertLogged(t testing.TB, m ...Matcher) *verbose.Entry {
+	t.Helper()
+	e := h.First(m...)
+	if e == nil {
+		t.Errorf("No matching entry logged. Got %q", h.Messages())
+	}
+	return e
+}
+
+// AssertNotLogged fails the test if a recorded entry matches all of m.
+func (h *Handler) AssertNotLogged(t testing.TB, m ...Matcher) {
+	t.Helper()
+	if e := h.First(m...); e != nil {
+		t.Errorf("Unexpected entry logged: %s: %q", e.Level, e.Message)
+	}
+}
+
+// AssertCount fails the test if the number of recorded entries matching all
+// of m isn't n.
+func (h *Handler) AssertCount(t testing.TB, n int, m ...Matcher) {
+	t.Helper()
+	if c := h.Count(m...); c != n {
+		t.Errorf("Incorrect number of matching entries. Expected %d, got %d", n, c)
+	}
+}
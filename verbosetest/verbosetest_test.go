@@ -0,0 +1,104 @@
+package verbosetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lfkeitel/verbose/v4"
+)
+
+func TestHandlerFind(t *testing.T) {
+	logger, h := NewLogger("verbosetest")
+	defer logger.Close()
+
+	logger.Debug("starting")
+	logger.WithField("user", "root").Info("logged in")
+	logger.WithField("user", "guest").Warning("login failed")
+	logger.Log(verbose.LogLevelTrace, "tracing")
+
+	if c := h.Count(); c != 4 {
+		t.Errorf("Incorrect count. Expected 4, got %d", c)
+	}
+	if c := h.Count(HasField("user")); c != 2 {
+		t.Errorf("Incorrect count with field. Expected 2, got %d", c)
+	}
+
+	found := h.Find(Level(verbose.LogLevelInfo), Field("user", "root"))
+	if len(found) != 1 || found[0].Message != "logged in" {
+		t.Errorf("Incorrect entries found. Got %v", found)
+	}
+	if e := h.First(MessageContains("login"), Logger("verbosetest")); e == nil || e.Level != verbose.LogLevelWarning {
+		t.Errorf("Incorrect first entry. Got %v", e)
+	}
+	if e := h.First(Message("login")); e != nil {
+		t.Errorf("Expected no exact match, got %v", e)
+	}
+
+	h.AssertLogged(t, Level(verbose.LogLevelTrace), Message("tracing"))
+	h.AssertNotLogged(t, Level(verbose.LogLevelError))
+	h.AssertCount(t, 1, Field("user", "guest"))
+
+	h.Reset()
+	if c := h.Count(); c != 0 {
+		t.Errorf("Entries not reset. Got %d", c)
+	}
+}
+
+func TestHandlerLevels(t *testing.T) {
+	logger, h := NewLogger("verbosetest")
+	defer logger.Close()
+
+	h.SetMinLevel(verbose.LogLevelWarning)
+	logger.Info("dropped")
+	logger.Warning("kept")
+	if msgs := h.Messages(); len(msgs) != 1 || msgs[0] != "kept" {
+		t.Errorf("Incorrect messages. Got %v", msgs)
+	}
+}
+
+func TestHandlerWaitFor(t *testing.T) {
+	logger, h := NewLogger("verbosetest")
+	defer logger.Close()
+
+	logger.Info("before")
+	if e := h.WaitFor(time.Second, Message("before")); e == nil {
+		t.Error("Entry logged before waiting not found")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		logger.Info("other")
+		logger.Error("done")
+	}()
+	if e := h.WaitFor(time.Second, Level(verbose.LogLevelError)); e == nil || e.Message != "done" {
+		t.Errorf("Incorrect entry. Got %v", e)
+	}
+
+	if e := h.WaitFor(10*time.Millisecond, Message("never")); e != nil {
+		t.Errorf("Expected timeout, got %v", e)
+	}
+}
+
+func TestHandlerWaitForReset(t *testing.T) {
+	logger, h := NewLogger("verbosetest")
+	defer logger.Close()
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+
+	found := make(chan *verbose.Entry)
+	go func() { found <- h.WaitFor(time.Second, Message("target")) }()
+	time.Sleep(10 * time.Millisecond)
+
+	// The new entries replace the ones WaitFor already checked
+	h.Reset()
+	logger.Info("target")
+	logger.Info("four")
+	logger.Info("five")
+	logger.Info("six")
+
+	if e := <-found; e == nil {
+		t.Error("Entry logged after Reset not found")
+	}
+}
@@ -0,0 +1,40 @@
+package verbose
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// WriterHandler writes log messages to an io.Writer. Writes are serialized
+// so concurrent entries never interleave.
+type WriterHandler struct {
+	levelRange
+	formatterHolder
+	out io.Writer
+	m   sync.Mutex
+}
+
+// NewWriterHandler creates a WriterHandler writing to w with the
+// LineFormatter.
+func NewWriterHandler(w io.Writer) *WriterHandler {
+	return &WriterHandler{
+		levelRange:      newLevelRange(),
+		formatterHolder: formatterHolder{formatter: NewLineFormatter()},
+		out:             w,
+	}
+}
+
+// WriteLog writes the log message to the writer.
+func (w *WriterHandler) WriteLog(e *Entry) {
+	msg := w.getFormatter().FormatByte(e)
+
+	w.m.Lock()
+	defer w.m.Unlock()
+	if _, err := w.out.Write(msg); err != nil {
+		fmt.Printf("Error writing log: %v\n", err)
+	}
+}
+
+// Close satisfies the interface, NOOP. The writer isn't closed.
+func (w *WriterHandler) Close() {}
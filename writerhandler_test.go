@@ -0,0 +1,55 @@
+package verbose
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriterHandler(t *testing.T) {
+	clearLoggers()
+	buf := &bytes.Buffer{}
+	wh := NewWriterHandler(buf)
+	logger := New("logger")
+	logger.AddHandler("writer", wh)
+
+	logger.Debug("one")
+	logger.Error("two")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Incorrect number of lines. Expected 2, got %d", len(lines))
+	}
+	if !strings.HasSuffix(lines[0], "DEBUG: logger: one") || !strings.HasSuffix(lines[1], "ERROR: logger: two") {
+		t.Errorf("Incorrect output. Got %q", lines)
+	}
+}
+
+func TestWriterHandlerConcurrent(t *testing.T) {
+	clearLoggers()
+	buf := &bytes.Buffer{}
+	logger := New("logger")
+	logger.AddHandler("writer", NewWriterHandler(buf))
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				logger.Info("Concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 200 {
+		t.Fatalf("Incorrect number of lines. Expected 200, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasSuffix(line, "INFO: logger: Concurrent") {
+			t.Fatalf("Interleaved line: %q", line)
+		}
+	}
+}